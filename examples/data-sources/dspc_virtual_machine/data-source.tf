@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    dspc = {
+      source  = "dspc/dspc"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "dspc" {
+  # REQUIRED: Configure via environment variables (recommended)
+  # DSPC_ENDPOINT="https://vm-deployer.example.com:8080"
+  # DSPC_API_KEY="your-api-key-here"
+  # DSPC_TIMEOUT="60"  # Optional, defaults to 30
+}
+
+# Look up a single virtual machine by name
+data "dspc_virtual_machine" "web" {
+  name = "web-server"
+}
+
+# Output the VM status
+output "vm_status" {
+  description = "The provisioning status of the virtual machine"
+  value       = data.dspc_virtual_machine.web.status
+}
@@ -17,6 +17,16 @@ provider "dspc" {
 # List all virtual machines
 data "dspc_virtual_machines" "all" {}
 
+# List only the running web servers of one team
+data "dspc_virtual_machines" "web" {
+  name_prefix = "web-"
+  status      = "running"
+
+  labels = {
+    team = "web"
+  }
+}
+
 # Output all VM names
 output "vm_names" {
   description = "List of all virtual machine names"
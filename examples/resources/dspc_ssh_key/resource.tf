@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    dspc = {
+      source  = "dspc/dspc"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "dspc" {
+  # REQUIRED: Configure via environment variables (recommended)
+  # DSPC_ENDPOINT="https://vm-deployer.example.com:8080"
+  # DSPC_API_KEY="your-api-key-here"
+}
+
+# Register an SSH public key
+resource "dspc_ssh_key" "example" {
+  name       = "alice"
+  public_key = file("~/.ssh/id_ed25519.pub")
+}
+
+# Install the key on a virtual machine when it is created
+resource "dspc_virtual_machine" "example" {
+  name          = "my-example-vm"
+  ssh_key_names = [dspc_ssh_key.example.name]
+}
+
+output "ssh_key_fingerprint" {
+  description = "The SHA256 fingerprint of the registered key"
+  value       = dspc_ssh_key.example.fingerprint
+}
@@ -16,7 +16,31 @@ provider "dspc" {
 
 # Create a virtual machine
 resource "dspc_virtual_machine" "example" {
-  name = "my-example-vm"
+  name      = "my-example-vm"
+  cpu       = 2
+  memory_mb = 4096
+  disk_gb   = 50
+  image     = "ubuntu-22.04"
+
+  description = "Example web server"
+
+  labels = {
+    team        = "web"
+    cost-center = "1234"
+  }
+
+  user_data = <<-EOT
+    #cloud-config
+    packages:
+      - nginx
+  EOT
+
+  # Names of dspc_ssh_key resources to install on the VM
+  ssh_key_names = ["alice"]
+
+  timeouts {
+    create = "30m"
+  }
 }
 
 # Output the VM details
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenExpiryDelta is how long before its expiry a cached access token is refreshed, so that
+// requests made during long applies never carry a token that expires in flight
+const tokenExpiryDelta = time.Minute
+
+// TokenProvider supplies bearer tokens for API requests. It replaces the static API key
+// when configured.
+type TokenProvider interface {
+	// Token returns a valid access token, fetching a new one when necessary
+	Token(ctx context.Context) (string, error)
+	// Invalidate discards the cached token after the API rejected it
+	Invalidate()
+}
+
+// WithTokenProvider authenticates requests with tokens from provider instead of the static API key
+func WithTokenProvider(provider TokenProvider) ClientOption {
+	return func(c *Client) {
+		c.auth = provider
+	}
+}
+
+// OAuth2Config describes an OAuth2 client credentials grant
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// OAuth2TokenSource fetches access tokens using the OAuth2 client credentials grant and
+// caches them until shortly before they expire
+type OAuth2TokenSource struct {
+	config     OAuth2Config
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// oauth2TokenResponse is the token endpoint response defined in RFC 6749 section 5.1
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// oauth2ErrorResponse is the token endpoint error response defined in RFC 6749 section 5.2
+type oauth2ErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// NewOAuth2TokenSource creates a token source that requests tokens with httpClient
+func NewOAuth2TokenSource(config OAuth2Config, httpClient *http.Client) *OAuth2TokenSource {
+	return &OAuth2TokenSource{config: config, httpClient: httpClient}
+}
+
+// Token returns the cached access token, or fetches a new one when none is cached or the
+// cached token is about to expire. Concurrent callers share a single fetch.
+func (s *OAuth2TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || time.Until(s.expiry) > tokenExpiryDelta) {
+		return s.token, nil
+	}
+
+	token, expiry, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token, s.expiry = token, expiry
+	return token, nil
+}
+
+// Invalidate discards the cached token so the next call to Token fetches a new one
+func (s *OAuth2TokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = ""
+	s.expiry = time.Time{}
+}
+
+// fetch requests a new access token from the token endpoint
+func (s *OAuth2TokenSource) fetch(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			tflog.Warn(ctx, "Failed to close token response body", map[string]interface{}{"error": err.Error()})
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr oauth2ErrorResponse
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			if oauthErr.ErrorDescription != "" {
+				return "", time.Time{}, fmt.Errorf("token request failed with status %d: %s: %s",
+					resp.StatusCode, oauthErr.Error, oauthErr.ErrorDescription)
+			}
+			return "", time.Time{}, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, oauthErr.Error)
+		}
+		return "", time.Time{}, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+
+	var token oauth2TokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("token response did not contain an access token")
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return "", time.Time{}, fmt.Errorf("unsupported token type %q", token.TokenType)
+	}
+
+	// A token without a lifetime is reused until the API rejects it
+	var expiry time.Time
+	if token.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return token.AccessToken, expiry, nil
+}
+
+// OAuth2Model describes the oauth2 block of the provider configuration
+type OAuth2Model struct {
+	TokenURL     types.String `tfsdk:"token_url"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Scopes       types.List   `tfsdk:"scopes"`
+}
+
+// oauth2ConfigFromConfig resolves the OAuth2 settings from provider configuration with
+// environment variable and profile fallbacks. It returns nil when OAuth2 is not configured.
+func oauth2ConfigFromConfig(
+	ctx context.Context,
+	config DspcProviderModel,
+	sources *configSources,
+) (*OAuth2Config, error) {
+	model := OAuth2Model{}
+	if config.OAuth2 != nil {
+		model = *config.OAuth2
+	}
+
+	oauth := &OAuth2Config{
+		TokenURL:     sources.stringSetting(model.TokenURL, "DSPC_OAUTH2_TOKEN_URL"),
+		ClientID:     sources.stringSetting(model.ClientID, "DSPC_OAUTH2_CLIENT_ID"),
+		ClientSecret: sources.stringSetting(model.ClientSecret, "DSPC_OAUTH2_CLIENT_SECRET"),
+	}
+
+	if !model.Scopes.IsNull() && !model.Scopes.IsUnknown() {
+		if diags := model.Scopes.ElementsAs(ctx, &oauth.Scopes, false); diags.HasError() {
+			return nil, fmt.Errorf("invalid oauth2 scopes")
+		}
+	} else if envScopes, _ := sources.lookup("DSPC_OAUTH2_SCOPES"); envScopes != "" {
+		oauth.Scopes = strings.FieldsFunc(envScopes, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+
+	if oauth.TokenURL == "" && oauth.ClientID == "" && oauth.ClientSecret == "" {
+		if config.OAuth2 != nil {
+			return nil, fmt.Errorf("the oauth2 block requires token_url, client_id and client_secret")
+		}
+		return nil, nil
+	}
+
+	var missing []string
+	if oauth.TokenURL == "" {
+		missing = append(missing, "token_url (DSPC_OAUTH2_TOKEN_URL)")
+	}
+	if oauth.ClientID == "" {
+		missing = append(missing, "client_id (DSPC_OAUTH2_CLIENT_ID)")
+	}
+	if oauth.ClientSecret == "" {
+		missing = append(missing, "client_secret (DSPC_OAUTH2_CLIENT_SECRET)")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("incomplete OAuth2 configuration, missing: %s", strings.Join(missing, ", "))
+	}
+
+	if _, err := url.ParseRequestURI(oauth.TokenURL); err != nil {
+		return nil, fmt.Errorf("invalid oauth2 token_url: %w", err)
+	}
+
+	return oauth, nil
+}
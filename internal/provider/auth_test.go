@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// newTokenServer starts a stand-in OAuth2 issuer that hands out numbered tokens valid for expiresIn seconds
+func newTokenServer(t *testing.T, expiresIn int64, issued *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse token request: %v", err)
+		}
+		if r.Form.Get("grant_type") != "client_credentials" {
+			t.Errorf("Expected client_credentials grant, got %q", r.Form.Get("grant_type"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "terraform" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(oauth2ErrorResponse{
+				Error:            "invalid_client",
+				ErrorDescription: "client authentication failed",
+			})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(oauth2TokenResponse{
+			AccessToken: fmt.Sprintf("token-%d", issued.Add(1)),
+			TokenType:   "Bearer",
+			ExpiresIn:   expiresIn,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestOAuth2TokenSource_CachesToken(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := newTokenServer(t, 3600, &issued)
+
+	var authHeaders []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*VM{})
+	}))
+	defer apiServer.Close()
+
+	client := NewClient(apiServer.URL, "", 30, WithListCacheTTL(0))
+	client.auth = NewOAuth2TokenSource(OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "terraform",
+		ClientSecret: "s3cret",
+	}, client.httpClient)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ListVMs(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if got := issued.Load(); got != 1 {
+		t.Errorf("Expected 1 token request, got %d", got)
+	}
+	for _, header := range authHeaders {
+		if header != "Bearer token-1" {
+			t.Errorf("Expected Authorization: Bearer token-1, got %s", header)
+		}
+	}
+}
+
+func TestOAuth2TokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	var issued atomic.Int32
+	// Tokens expiring within the refresh margin are never reused
+	tokenServer := newTokenServer(t, int64(tokenExpiryDelta.Seconds())/2, &issued)
+
+	source := NewOAuth2TokenSource(OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "terraform",
+		ClientSecret: "s3cret",
+	}, http.DefaultClient)
+
+	first, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first == second {
+		t.Errorf("Expected token close to expiry to be refreshed, got %s twice", first)
+	}
+}
+
+func TestOAuth2TokenSource_RetriesOnceOnUnauthorized(t *testing.T) {
+	tests := []struct {
+		name             string
+		acceptedToken    string
+		expectError      bool
+		expectedTokens   int32
+		expectedRequests int32
+	}{
+		{
+			name:             "revoked token replaced",
+			acceptedToken:    "Bearer token-2",
+			expectedTokens:   2,
+			expectedRequests: 2,
+		},
+		{
+			name:             "new token also rejected",
+			acceptedToken:    "Bearer never",
+			expectError:      true,
+			expectedTokens:   2,
+			expectedRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var issued, requests atomic.Int32
+			tokenServer := newTokenServer(t, 3600, &issued)
+
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				if r.Header.Get("Authorization") != tt.acceptedToken {
+					w.WriteHeader(http.StatusUnauthorized)
+					_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid token"})
+					return
+				}
+				_ = json.NewEncoder(w).Encode([]*VM{})
+			}))
+			defer apiServer.Close()
+
+			client := NewClient(apiServer.URL, "", 30)
+			client.auth = NewOAuth2TokenSource(OAuth2Config{
+				TokenURL:     tokenServer.URL,
+				ClientID:     "terraform",
+				ClientSecret: "s3cret",
+			}, client.httpClient)
+
+			_, err := client.ListVMs(context.Background())
+			if tt.expectError {
+				if !IsUnauthorized(err) {
+					t.Errorf("Expected unauthorized error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+
+			if got := issued.Load(); got != tt.expectedTokens {
+				t.Errorf("Expected %d token requests, got %d", tt.expectedTokens, got)
+			}
+			if got := requests.Load(); got != tt.expectedRequests {
+				t.Errorf("Expected %d API requests, got %d", tt.expectedRequests, got)
+			}
+		})
+	}
+}
+
+func TestOAuth2TokenSource_TokenError(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := newTokenServer(t, 3600, &issued)
+
+	client := NewClient("http://127.0.0.1:1", "", 30)
+	client.auth = NewOAuth2TokenSource(OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "terraform",
+		ClientSecret: "wrong",
+	}, client.httpClient)
+
+	_, err := client.ListVMs(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_client: client authentication failed") {
+		t.Errorf("Expected token error from issuer, got %v", err)
+	}
+}
+
+func TestNewClientFromConfig_OAuth2(t *testing.T) {
+	tests := []struct {
+		name             string
+		oauth2           *OAuth2Model
+		env              map[string]string
+		expectError      bool
+		expectedErrorMsg string
+		expectOAuth2     bool
+		expectedScopes   []string
+	}{
+		{
+			name: "oauth2 block replaces API key",
+			oauth2: &OAuth2Model{
+				TokenURL:     types.StringValue("https://issuer.example.com/token"),
+				ClientID:     types.StringValue("terraform"),
+				ClientSecret: types.StringValue("s3cret"),
+				Scopes:       types.ListValueMust(types.StringType, nil),
+			},
+			expectOAuth2:   true,
+			expectedScopes: []string{},
+		},
+		{
+			name: "oauth2 from environment",
+			env: map[string]string{
+				"DSPC_OAUTH2_TOKEN_URL":     "https://issuer.example.com/token",
+				"DSPC_OAUTH2_CLIENT_ID":     "terraform",
+				"DSPC_OAUTH2_CLIENT_SECRET": "s3cret",
+				"DSPC_OAUTH2_SCOPES":        "vm.read, vm.write",
+			},
+			expectOAuth2:   true,
+			expectedScopes: []string{"vm.read", "vm.write"},
+		},
+		{
+			name: "incomplete oauth2 block",
+			oauth2: &OAuth2Model{
+				TokenURL:     types.StringValue("https://issuer.example.com/token"),
+				ClientID:     types.StringNull(),
+				ClientSecret: types.StringNull(),
+				Scopes:       types.ListNull(types.StringType),
+			},
+			expectError:      true,
+			expectedErrorMsg: "missing: client_id (DSPC_OAUTH2_CLIENT_ID), client_secret",
+		},
+		{
+			name:             "no credentials",
+			expectError:      true,
+			expectedErrorMsg: "API key is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, envVar := range []string{"DSPC_API_KEY", "DSPC_OAUTH2_TOKEN_URL", "DSPC_OAUTH2_CLIENT_ID",
+				"DSPC_OAUTH2_CLIENT_SECRET", "DSPC_OAUTH2_SCOPES"} {
+				t.Setenv(envVar, tt.env[envVar])
+			}
+
+			client, err := NewClientFromConfig(DspcProviderModel{
+				Endpoint: types.StringValue("http://localhost:8080"),
+				APIKey:   types.StringNull(),
+				OAuth2:   tt.oauth2,
+			})
+
+			if tt.expectError {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErrorMsg) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectedErrorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			source, ok := client.auth.(*OAuth2TokenSource)
+			if ok != tt.expectOAuth2 {
+				t.Fatalf("Expected OAuth2 token source=%t, got %T", tt.expectOAuth2, client.auth)
+			}
+			if strings.Join(source.config.Scopes, " ") != strings.Join(tt.expectedScopes, " ") {
+				t.Errorf("Expected scopes %v, got %v", tt.expectedScopes, source.config.Scopes)
+			}
+			if source.httpClient != client.httpClient {
+				t.Error("Expected token requests to share the API HTTP client")
+			}
+		})
+	}
+}
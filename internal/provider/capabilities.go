@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// FeatureVMSpec is the capability covering the cpu, memoryMb, diskGb, image and description
+// VM fields, which servers implementing only the minimal API ignore
+const FeatureVMSpec = "vm.spec"
+
+// FeatureVMLabels is the capability covering the labels VM field
+const FeatureVMLabels = "vm.labels"
+
+// FeatureVMUserData is the capability covering the userData VM field
+const FeatureVMUserData = "vm.userData"
+
+// FeatureSSHKeys is the capability covering the /sshkey endpoints and the sshKeyNames VM field
+const FeatureSSHKeys = "sshKeys"
+
+// Capabilities describes the API version and optional features of a DSPC server
+type Capabilities struct {
+	APIVersion string   `json:"apiVersion"`
+	Features   []string `json:"features"`
+}
+
+// capabilityRequirement is the API support an attribute needs: either the named feature or
+// at least the given API version
+type capabilityRequirement struct {
+	Attribute  string
+	Feature    string
+	MinVersion string
+}
+
+// vmCapabilityRequirements lists the dspc_virtual_machine attributes that older servers ignore
+var vmCapabilityRequirements = []capabilityRequirement{
+	{Attribute: "cpu", Feature: FeatureVMSpec, MinVersion: "2.0"},
+	{Attribute: "memory_mb", Feature: FeatureVMSpec, MinVersion: "2.0"},
+	{Attribute: "disk_gb", Feature: FeatureVMSpec, MinVersion: "2.0"},
+	{Attribute: "image", Feature: FeatureVMSpec, MinVersion: "2.0"},
+	{Attribute: "description", Feature: FeatureVMSpec, MinVersion: "2.0"},
+	{Attribute: "labels", Feature: FeatureVMLabels, MinVersion: "2.1"},
+	{Attribute: "user_data", Feature: FeatureVMUserData, MinVersion: "2.1"},
+	{Attribute: "user_data_base64", Feature: FeatureVMUserData, MinVersion: "2.1"},
+	{Attribute: "ssh_key_names", Feature: FeatureSSHKeys, MinVersion: "2.2"},
+}
+
+// sshKeyCapabilityRequirements gates the dspc_ssh_key resource, which only servers with the
+// /sshkey endpoints support. The requirement is placed on name since it is always configured.
+var sshKeyCapabilityRequirements = []capabilityRequirement{
+	{Attribute: "name", Feature: FeatureSSHKeys, MinVersion: "2.2"},
+}
+
+// Supports reports whether the server advertises feature or implements at least minVersion
+// of the API
+func (c *Capabilities) Supports(feature, minVersion string) bool {
+	if slices.Contains(c.Features, feature) {
+		return true
+	}
+	return compareAPIVersions(c.APIVersion, minVersion) >= 0
+}
+
+// compareAPIVersions compares two "major.minor" versions, returning -1, 0 or 1. Missing or
+// malformed components count as zero.
+func compareAPIVersions(a, b string) int {
+	aParts := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bParts := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var aPart, bPart int
+		if i < len(aParts) {
+			aPart, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bPart, _ = strconv.Atoi(bParts[i])
+		}
+		if aPart != bPart {
+			if aPart < bPart {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Capabilities returns the capabilities advertised by the server through GET /capabilities.
+// It returns nil without an error when the server does not implement the endpoint, in which
+// case nothing is known about its version. The result is fetched once per client.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	c.capabilitiesMu.Lock()
+	defer c.capabilitiesMu.Unlock()
+
+	if c.capabilitiesLoaded {
+		return c.capabilities, nil
+	}
+
+	var capabilities Capabilities
+	err := c.do(ctx, http.MethodGet, "/capabilities", nil, &capabilities)
+	switch {
+	case err == nil:
+		c.capabilities = &capabilities
+	case IsNotFound(err) || isUnsupportedEndpoint(err):
+		c.capabilities = nil
+	default:
+		return nil, fmt.Errorf("failed to discover API capabilities: %w", err)
+	}
+
+	c.capabilitiesLoaded = true
+	if c.capabilities != nil {
+		tflog.Info(ctx, "Discovered DSPC API capabilities", map[string]interface{}{
+			"api_version": c.capabilities.APIVersion,
+			"features":    c.capabilities.Features,
+		})
+	}
+	return c.capabilities, nil
+}
+
+// checkCapabilities adds an attribute error for each configured value in values whose
+// requirement the server does not meet. Nothing is checked for servers that do not
+// advertise their capabilities.
+func checkCapabilities(
+	ctx context.Context,
+	client *Client,
+	requirements []capabilityRequirement,
+	values map[string]attr.Value,
+	diags *diag.Diagnostics,
+) {
+	if client == nil {
+		return
+	}
+
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		diags.AddWarning("Unable to Determine DSPC API Capabilities",
+			fmt.Sprintf("Attributes that require a newer API version could not be checked: %s", err))
+		return
+	}
+	if capabilities == nil {
+		return
+	}
+
+	for _, requirement := range requirements {
+		value, ok := values[requirement.Attribute]
+		if !ok || value.IsNull() {
+			continue
+		}
+		if capabilities.Supports(requirement.Feature, requirement.MinVersion) {
+			continue
+		}
+
+		diags.AddAttributeError(
+			path.Root(requirement.Attribute),
+			"Unsupported Attribute",
+			fmt.Sprintf("%s requires DSPC API >= %s, but the server reports API version %s without the %q "+
+				"capability. Remove the attribute or upgrade the DSPC API.",
+				requirement.Attribute, requirement.MinVersion, capabilities.APIVersion, requirement.Feature),
+		)
+	}
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCompareAPIVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "2.0", b: "2.0", expected: 0},
+		{a: "2", b: "2.0", expected: 0},
+		{a: "v2.1", b: "2.0", expected: 1},
+		{a: "1.9", b: "2.0", expected: -1},
+		{a: "2.10", b: "2.9", expected: 1},
+		{a: "", b: "2.0", expected: -1},
+		{a: "unknown", b: "2.0", expected: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if result := compareAPIVersions(tt.a, tt.b); result != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCapabilities_Supports(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities Capabilities
+		expected     bool
+	}{
+		{name: "newer version", capabilities: Capabilities{APIVersion: "2.1"}, expected: true},
+		{
+			name:         "feature advertised",
+			capabilities: Capabilities{APIVersion: "1.0", Features: []string{FeatureVMSpec}},
+			expected:     true,
+		},
+		{name: "older version", capabilities: Capabilities{APIVersion: "1.0"}, expected: false},
+		{
+			name:         "other feature",
+			capabilities: Capabilities{APIVersion: "1.0", Features: []string{"vm.other"}},
+			expected:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.capabilities.Supports(FeatureVMSpec, "2.0"); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestClient_Capabilities(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockStatusCode int
+		mockResponse   string
+		expected       *Capabilities
+		expectError    bool
+	}{
+		{
+			name:           "advertised",
+			mockStatusCode: http.StatusOK,
+			mockResponse:   `{"apiVersion":"2.0","features":["vm.spec"]}`,
+			expected:       &Capabilities{APIVersion: "2.0", Features: []string{FeatureVMSpec}},
+		},
+		{name: "not implemented", mockStatusCode: http.StatusNotFound},
+		{name: "method not allowed", mockStatusCode: http.StatusMethodNotAllowed},
+		{name: "server error", mockStatusCode: http.StatusInternalServerError, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != "/capabilities" {
+					t.Errorf("Expected request to /capabilities, got %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.mockStatusCode)
+				_, _ = w.Write([]byte(tt.mockResponse))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(testRetryPolicy))
+
+			for i := 0; i < 2; i++ {
+				result, err := client.Capabilities(context.Background())
+				if tt.expectError {
+					if err == nil {
+						t.Fatal("Expected error, got none")
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if (result == nil) != (tt.expected == nil) {
+					t.Fatalf("Expected %+v, got %+v", tt.expected, result)
+				}
+				if result != nil && (result.APIVersion != tt.expected.APIVersion ||
+					len(result.Features) != len(tt.expected.Features)) {
+					t.Errorf("Expected %+v, got %+v", tt.expected, result)
+				}
+			}
+
+			// Failed discovery is attempted again on the next call; anything else is fetched once
+			expectedRequests := 1
+			if tt.expectError {
+				expectedRequests = 2
+			}
+			if requests != expectedRequests {
+				t.Errorf("Expected %d requests, got %d", expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestVirtualMachineResource_ModifyPlan_Capabilities(t *testing.T) {
+	tests := []struct {
+		name            string
+		capabilities    string
+		model           VMResourceModel
+		expectedAttrErr string
+	}{
+		{
+			name:         "supported",
+			capabilities: `{"apiVersion":"2.0"}`,
+			model:        VMResourceModel{Name: types.StringValue("test-vm"), DiskGB: types.Int64Value(100)},
+		},
+		{
+			name:         "unsupported attribute unset",
+			capabilities: `{"apiVersion":"1.0"}`,
+			model:        VMResourceModel{Name: types.StringValue("test-vm")},
+		},
+		{
+			name:            "unsupported attribute set",
+			capabilities:    `{"apiVersion":"1.0"}`,
+			model:           VMResourceModel{Name: types.StringValue("test-vm"), DiskGB: types.Int64Value(100)},
+			expectedAttrErr: "disk_gb",
+		},
+		{
+			name:         "labels on older server",
+			capabilities: `{"apiVersion":"2.0"}`,
+			model: VMResourceModel{
+				Name:   types.StringValue("test-vm"),
+				Labels: labelsValue(map[string]string{"team": "platform"}),
+			},
+			expectedAttrErr: "labels",
+		},
+		{
+			name:         "capabilities not advertised",
+			capabilities: "",
+			model:        VMResourceModel{Name: types.StringValue("test-vm"), DiskGB: types.Int64Value(100)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.capabilities == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.capabilities))
+			}))
+			defer server.Close()
+
+			vmResource := &VMResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+			plan := newVMResourcePlan(t, vmResource, tt.model)
+			config := tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}
+			resp := &resource.ModifyPlanResponse{Plan: plan}
+
+			vmResource.ModifyPlan(context.Background(), resource.ModifyPlanRequest{Config: config, Plan: plan}, resp)
+
+			if tt.expectedAttrErr == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("Expected no error, got %v", resp.Diagnostics)
+				}
+				return
+			}
+
+			if resp.Diagnostics.ErrorsCount() != 1 {
+				t.Fatalf("Expected 1 error, got %v", resp.Diagnostics)
+			}
+			d := resp.Diagnostics.Errors()[0]
+			withPath, ok := d.(interface{ Path() path.Path })
+			if !ok || !withPath.Path().Equal(path.Root(tt.expectedAttrErr)) {
+				t.Errorf("Expected diagnostic on %s attribute, got %v", tt.expectedAttrErr, d)
+			}
+			if !strings.Contains(d.Detail(), tt.expectedAttrErr+" requires DSPC API >= ") {
+				t.Errorf("Expected detail naming the required version, got %s", d.Detail())
+			}
+		})
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
 )
 
 // Client represents the DSPC API client
@@ -19,11 +23,93 @@ type Client struct {
 	httpClient *http.Client
 	endpoint   string
 	apiKey     string
+	// auth replaces apiKey as the source of bearer tokens when set
+	auth  TokenProvider
+	retry RetryPolicy
+
+	// directLookup records whether the server supports GET /virtualmachine/{name}
+	directLookup atomic.Int32
+	vmList       *vmListCache
+	pollInterval time.Duration
+
+	// limiter and inflight throttle API requests when set
+	limiter  *rate.Limiter
+	inflight chan struct{}
+
+	userAgent string
+
+	// defaultLabels are merged into the labels of every VM resource
+	defaultLabels map[string]string
+
+	// capabilities caches the result of GET /capabilities once capabilitiesLoaded is set
+	capabilitiesMu     sync.Mutex
+	capabilities       *Capabilities
+	capabilitiesLoaded bool
+}
+
+// Support states for the per-VM lookup endpoint
+const (
+	lookupUnknown int32 = iota
+	lookupSupported
+	lookupUnsupported
+)
+
+// errUnexpectedLookupResponse is returned when the per-VM endpoint answers with something other than a VM
+var errUnexpectedLookupResponse = errors.New("unexpected response from VM lookup endpoint")
+
+// ClientOption customizes a Client created by NewClient
+type ClientOption func(*Client)
+
+// WithRetryPolicy sets the policy used to retry transient API failures
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
 }
 
-// VM represents a virtual machine in the DSPC API
+// WithListCacheTTL sets how long a fetched VM list is reused. A ttl of zero disables the cache.
+func WithListCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.vmList = newVMListCache(ttl)
+	}
+}
+
+// VM represents a virtual machine in the DSPC API. Servers implementing only the minimal
+// API return just the name; the sizing and image fields are then left at their zero values.
 type VM struct {
-	Name string `json:"vmName"`
+	Name        string `json:"vmName"`
+	CPU         int64  `json:"cpu,omitempty"`
+	MemoryMB    int64  `json:"memoryMb,omitempty"`
+	DiskGB      int64  `json:"diskGb,omitempty"`
+	Image       string `json:"image,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Status is the provisioning status reported by the API, such as "provisioning" or "running"
+	Status string            `json:"status,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// UserData is the base64-encoded cloud-init user data
+	UserData string `json:"userData,omitempty"`
+	// SSHKeyNames are the names of the SSH keys installed when the VM is created
+	SSHKeyNames []string `json:"sshKeyNames,omitempty"`
+	// IdempotencyKey is the Idempotency-Key of the create request, reported by servers that
+	// store it with the VM
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// VMUpdate describes changes to the mutable properties of a virtual machine. Only non-nil
+// fields are sent to the API.
+type VMUpdate struct {
+	CPU         *int64  `json:"cpu,omitempty"`
+	MemoryMB    *int64  `json:"memoryMb,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// Labels replaces all labels of the VM. It is a pointer so that removing the last label
+	// can be sent as an empty object.
+	Labels   *map[string]string `json:"labels,omitempty"`
+	UserData *string            `json:"userData,omitempty"`
+}
+
+// IsEmpty reports whether the update contains no changes
+func (u VMUpdate) IsEmpty() bool {
+	return u == VMUpdate{}
 }
 
 // CreateVMResponse represents the response from creating a VM
@@ -37,60 +123,81 @@ type DeleteVMResponse struct {
 }
 
 // NewClient creates a new DSPC API client
-func NewClient(endpoint, apiKey string, timeoutSeconds int64) *Client {
+func NewClient(endpoint, apiKey string, timeoutSeconds int64, opts ...ClientOption) *Client {
 	timeout := time.Duration(timeoutSeconds) * time.Second
 	if timeoutSeconds == 0 {
 		timeout = 30 * time.Second // default timeout
 	}
 
-	return &Client{
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
 		endpoint: endpoint,
 		apiKey:   apiKey,
-	}
-}
+		retry:    DefaultRetryPolicy(),
+		vmList:   newVMListCache(defaultListCacheTTL),
 
-// NewClientFromConfig creates a client from provider configuration with environment variable fallbacks
-func NewClientFromConfig(config DspcProviderModel) (*Client, error) {
-	var endpoint, apiKey string
-	var timeoutSeconds int64
+		pollInterval: defaultPollInterval,
+		userAgent:    defaultUserAgent,
+	}
 
-	// Extract endpoint with environment fallback
-	if !config.Endpoint.IsNull() {
-		endpoint = config.Endpoint.ValueString()
+	for _, opt := range opts {
+		opt(c)
 	}
-	if endpoint == "" {
-		endpoint = os.Getenv("DSPC_ENDPOINT")
+
+	return c
+}
+
+// NewClientFromConfig creates a client from provider configuration. Settings that are not set
+// as attributes fall back to environment variables and then to the selected profile. The
+// given options are applied after those derived from the configuration.
+func NewClientFromConfig(config DspcProviderModel, extraOpts ...ClientOption) (*Client, error) {
+	sources, err := loadConfigSources(config)
+	if err != nil {
+		return nil, err
 	}
 
+	// Extract endpoint with environment and profile fallback
+	endpoint := sources.stringSetting(config.Endpoint, "DSPC_ENDPOINT")
+
 	// Validate that endpoint is provided
 	if endpoint == "" {
 		return nil, fmt.Errorf("endpoint is required but not provided. Please set the 'endpoint' attribute " +
-			"in the provider configuration or set the DSPC_ENDPOINT environment variable")
+			"in the provider configuration, set the DSPC_ENDPOINT environment variable, or select a profile " +
+			"that sets it")
 	}
+	if err := validateEndpoint(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	// Extract API key with environment and profile fallback
+	apiKey := sources.stringSetting(config.APIKey, "DSPC_API_KEY")
 
-	// Extract API key with environment fallback
-	if !config.APIKey.IsNull() {
-		apiKey = config.APIKey.ValueString()
+	oauth, err := oauth2ConfigFromConfig(context.Background(), config, sources)
+	if err != nil {
+		return nil, err
 	}
-	if apiKey == "" {
-		apiKey = os.Getenv("DSPC_API_KEY")
+
+	credentialProcess := sources.stringSetting(config.CredentialProcess, "DSPC_CREDENTIAL_PROCESS")
+	if credentialProcess != "" && oauth != nil {
+		return nil, fmt.Errorf("credential_process and oauth2 cannot be used together")
 	}
 
-	// Validate that API key is provided
-	if apiKey == "" {
+	// Validate that API key is provided unless a token provider is used instead
+	if apiKey == "" && oauth == nil && credentialProcess == "" {
 		return nil, fmt.Errorf("API key is required but not provided. Please set the 'api_key' attribute " +
-			"in the provider configuration or set the DSPC_API_KEY environment variable")
+			"in the provider configuration, set the DSPC_API_KEY environment variable, or configure " +
+			"'credential_process' or the 'oauth2' block")
 	}
 
 	// Extract timeout with defaults
+	var timeoutSeconds int64
 	if !config.Timeout.IsNull() {
 		timeoutSeconds = config.Timeout.ValueInt64()
 	}
 	if timeoutSeconds == 0 {
-		if envTimeout := os.Getenv("DSPC_TIMEOUT"); envTimeout != "" {
+		if envTimeout, _ := sources.lookup("DSPC_TIMEOUT"); envTimeout != "" {
 			if parsedTimeout, err := strconv.ParseInt(envTimeout, 10, 64); err == nil {
 				timeoutSeconds = parsedTimeout
 			}
@@ -100,144 +207,493 @@ func NewClientFromConfig(config DspcProviderModel) (*Client, error) {
 		}
 	}
 
-	return NewClient(endpoint, apiKey, timeoutSeconds), nil
-}
+	retry, err := retryPolicyFromConfig(config, sources)
+	if err != nil {
+		return nil, err
+	}
 
-// makeRequest makes an HTTP request to the DSPC API
-func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	listCacheTTL := defaultListCacheTTL
+	cacheSeconds, ok, err := sources.int64Setting(config.ListCacheTTL, "DSPC_LIST_CACHE_TTL")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if cacheSeconds < 0 {
+			return nil, fmt.Errorf("list_cache_ttl must not be negative, got %d", cacheSeconds)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
+		listCacheTTL = time.Duration(cacheSeconds) * time.Second
+	}
+
+	requestsPerSecond, maxConcurrent, err := rateLimitFromConfig(config, sources)
+	if err != nil {
+		return nil, err
 	}
 
-	// Construct URL properly
-	baseURL, err := url.Parse(c.endpoint)
+	defaultLabels, err := defaultLabelsFromConfig(context.Background(), config)
 	if err != nil {
-		return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+		return nil, err
 	}
 
-	pathURL, err := url.Parse(path)
+	opts := []ClientOption{
+		WithRetryPolicy(retry),
+		WithListCacheTTL(listCacheTTL),
+		WithRateLimit(float64(requestsPerSecond), int(requestsPerSecond)),
+		WithMaxConcurrentRequests(int(maxConcurrent)),
+		WithDefaultLabels(defaultLabels),
+	}
+
+	tlsSettings, err := tlsSettingsFromConfig(config, sources)
 	if err != nil {
-		return nil, fmt.Errorf("invalid path: %w", err)
+		return nil, err
+	}
+	if !tlsSettings.IsDefault() {
+		tlsConfig, err := tlsSettings.Build()
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+		opts = append(opts, WithTLSConfig(tlsConfig))
 	}
 
-	finalURL := baseURL.ResolveReference(pathURL)
+	if proxySettings := proxySettingsFromConfig(config, sources); !proxySettings.IsDefault() {
+		proxy, err := proxySettings.ProxyFunc()
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+		}
+		opts = append(opts, WithProxy(proxy))
+	}
 
-	req, err := http.NewRequestWithContext(ctx, method, finalURL.String(), reqBody)
+	client := NewClient(endpoint, apiKey, timeoutSeconds, append(opts, extraOpts...)...)
+
+	// The token endpoint is reached with the same HTTP client, so it shares the TLS settings
+	if oauth != nil {
+		client.auth = NewOAuth2TokenSource(*oauth, client.httpClient)
+	}
+	if credentialProcess != "" {
+		client.auth = NewCredentialProcess(credentialProcess)
+	}
+
+	return client, nil
+}
+
+// retryPolicyFromConfig builds the retry policy from provider configuration with environment
+// variable and profile fallbacks
+func retryPolicyFromConfig(config DspcProviderModel, sources *configSources) (RetryPolicy, error) {
+	policy := DefaultRetryPolicy()
+
+	maxRetries, ok, err := sources.int64Setting(config.MaxRetries, "DSPC_MAX_RETRIES")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return policy, err
+	}
+	if ok {
+		if maxRetries < 0 {
+			return policy, fmt.Errorf("max_retries must not be negative, got %d", maxRetries)
+		}
+		policy.MaxRetries = int(maxRetries)
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	minWait, ok, err := sources.int64Setting(config.RetryWaitMin, "DSPC_RETRY_WAIT_MIN")
+	if err != nil {
+		return policy, err
+	}
+	if ok {
+		if minWait < 0 {
+			return policy, fmt.Errorf("retry_wait_min must not be negative, got %d", minWait)
+		}
+		policy.MinBackoff = time.Duration(minWait) * time.Second
 	}
 
-	resp, err := c.httpClient.Do(req)
+	maxWait, ok, err := sources.int64Setting(config.RetryWaitMax, "DSPC_RETRY_WAIT_MAX")
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return policy, err
+	}
+	if ok {
+		if maxWait < 0 {
+			return policy, fmt.Errorf("retry_wait_max must not be negative, got %d", maxWait)
+		}
+		policy.MaxBackoff = time.Duration(maxWait) * time.Second
+	}
+
+	if policy.MinBackoff > policy.MaxBackoff {
+		return policy, fmt.Errorf("retry_wait_min (%s) must not be greater than retry_wait_max (%s)",
+			policy.MinBackoff, policy.MaxBackoff)
 	}
 
-	return resp, nil
+	return policy, nil
 }
 
-// CreateVM creates a new virtual machine
-func (c *Client) CreateVM(ctx context.Context, name string) (*VM, error) {
-	vm := VM{Name: name}
-	resp, err := c.makeRequest(ctx, http.MethodPost, "/virtualmachine", vm)
+// makeRequest makes an HTTP request to the DSPC API, retrying transient failures
+// according to the client's retry policy
+func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var jsonBody []byte
+	if body != nil {
+		var err error
+		jsonBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	finalURL, err := resolveURL(c.endpoint, path)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Printf("Warning: failed to close response body: %v", err)
+
+	// Retries of a call share its request ID so they can be found together in the server logs
+	requestID, err := newRequestID()
+	if err != nil {
+		return nil, err
+	}
+
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		// The body is rebuilt for every attempt since a previous attempt may have consumed it
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
 		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
+		req, err := http.NewRequestWithContext(ctx, method, finalURL.String(), reqBody)
 		if err != nil {
-			return nil, fmt.Errorf("API error %d: failed to read response body: %w", resp.StatusCode, err)
+			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
 
-	var createResp CreateVMResponse
-	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		// Set headers
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Header.Set(requestIDHeader, requestID)
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain access token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		op := idempotentOperationFromContext(ctx)
+		var idempotencyKey string
+		if op != nil {
+			idempotencyKey = op.key
+			req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+		}
+
+		logCtx := c.logContext(ctx, token)
+		logRequest(logCtx, req, jsonBody, attempt)
+
+		// Retries pass through the limits too, so a struggling server is not hit harder
+		release, err := c.acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to make request: %w", err)
+		}
+
+		start := time.Now()
+		resp, err := c.httpClient.Do(req)
+		holdUntilClosed(resp, release)
+		logResponse(logCtx, req, resp, err, time.Since(start))
+
+		// A rejected token may have been revoked or expired early, so a fresh token is
+		// requested once without counting against the retry budget
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && c.auth != nil && !reauthenticated {
+			reauthenticated = true
+			c.auth.Invalidate()
+			_, _ = io.Copy(io.Discard, resp.Body)
+			if err := resp.Body.Close(); err != nil {
+				tflog.SubsystemWarn(logCtx, logSubsystem, "Failed to close response body", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			attempt--
+			continue
+		}
+
+		idempotent := isIdempotentMethod(method) || idempotencyKey != ""
+		if attempt >= c.retry.MaxRetries || !shouldRetryRequest(ctx, idempotent, resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to make request (request ID: %s): %w", requestID, err)
+			}
+			return resp, nil
+		}
+
+		if op != nil {
+			op.retried = true
+		}
+
+		wait := c.retry.backoff(attempt, resp)
+		if resp != nil {
+			// Drain the body so the underlying connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			if err := resp.Body.Close(); err != nil {
+				tflog.SubsystemWarn(logCtx, logSubsystem, "Failed to close response body", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+
+		tflog.SubsystemDebug(logCtx, logSubsystem, "Retrying API request", map[string]interface{}{
+			"method":  method,
+			"url":     finalURL.String(),
+			"attempt": attempt + 1,
+			"wait_ms": wait.Milliseconds(),
+		})
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, fmt.Errorf("failed to make request (request ID: %s): %w", requestID, err)
+		}
 	}
+}
 
-	return &VM{Name: createResp.Created}, nil
+// token returns the bearer token for the next request
+func (c *Client) token(ctx context.Context) (string, error) {
+	if c.auth != nil {
+		return c.auth.Token(ctx)
+	}
+	return c.apiKey, nil
 }
 
-// DeleteVM deletes a virtual machine by name
-func (c *Client) DeleteVM(ctx context.Context, name string) error {
-	vm := VM{Name: name}
-	resp, err := c.makeRequest(ctx, http.MethodDelete, "/virtualmachine", vm)
+// do makes an HTTP request to the DSPC API and decodes a successful JSON response into out.
+// Non-successful responses are returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := c.makeRequest(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			log.Printf("Warning: failed to close response body: %v", err)
+			tflog.Warn(ctx, "Failed to close response body", map[string]interface{}{"error": err.Error()})
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("API error %d: failed to read response body: %w", resp.StatusCode, err)
-		}
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	return nil
 }
 
-// GetVM retrieves a virtual machine by name (checks if it exists)
-func (c *Client) GetVM(ctx context.Context, name string) (*VM, error) {
-	vms, err := c.ListVMs(ctx)
+// CreateVM creates a new virtual machine from the given specification
+func (c *Client) CreateVM(ctx context.Context, spec VM) (*VM, error) {
+	// The VM list changes even when the request fails partway, so the cache is always dropped
+	defer c.vmList.invalidate()
+
+	// All attempts of this create, and of a rerun of it, share one key, so a server that already
+	// processed a request whose response was lost recognizes the repeated create
+	key, err := idempotencyKeyFor(spec)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, vm := range vms {
-		if vm.Name == name {
-			return vm, nil
+	op := &idempotentOperation{key: key}
+	var createResp CreateVMResponse
+	if err := c.do(withIdempotencyKey(ctx, op), http.MethodPost, "/virtualmachine", spec, &createResp); err != nil {
+		if IsConflict(err) {
+			return c.adoptExistingVM(ctx, spec, key, op.retried, err)
 		}
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("VM '%s' not found. Please verify the VM name exists or check your API endpoint", name)
+	// The API only confirms the name, so the remaining fields are taken from the request
+	vm := spec
+	vm.Name = createResp.Created
+	return &vm, nil
 }
 
-// ListVMs retrieves all virtual machines
-func (c *Client) ListVMs(ctx context.Context) ([]*VM, error) {
-	resp, err := c.makeRequest(ctx, http.MethodGet, "/virtualmachine", nil)
+// adoptExistingVM handles a create that conflicted with an existing VM of the same name. This
+// happens when an earlier attempt, or an earlier run, created the VM but its response never
+// arrived, for example because of a timeout. The existing VM is returned as the created VM only
+// when it is shown to come from this create: either the API stored the idempotency key of the
+// create with the VM, or the create was retried and the VM reports its specification and it
+// matches spec. Otherwise the conflict is reported, since the VM may belong to someone else.
+func (c *Client) adoptExistingVM(
+	ctx context.Context,
+	spec VM,
+	key string,
+	retried bool,
+	conflict error,
+) (*VM, error) {
+	c.vmList.invalidate()
+
+	existing, err := c.GetVM(ctx, spec.Name)
 	if err != nil {
+		return nil, conflict
+	}
+
+	switch {
+	case existing.IdempotencyKey != "":
+		if existing.IdempotencyKey != key {
+			return nil, fmt.Errorf("VM '%s' already exists and was created by another operation: %w",
+				spec.Name, conflict)
+		}
+	case !retried:
+		// Without a stored key, only an attempt of this call can be shown to have made the VM
+		return nil, conflict
+	case !existing.reportsSpec():
+		return nil, fmt.Errorf("VM '%s' already exists and the API does not report enough of its configuration "+
+			"to show that this create made it: %w", spec.Name, conflict)
+	case !existing.matchesSpec(spec):
+		return nil, fmt.Errorf("VM '%s' already exists with a different configuration: %w", spec.Name, conflict)
+	}
+
+	tflog.Info(ctx, "Adopting existing VM matching the requested specification", map[string]interface{}{
+		"name": spec.Name,
+	})
+
+	// Fields the API does not report are taken from the request, as for a regular create
+	vm := *existing
+	if vm.CPU == 0 {
+		vm.CPU = spec.CPU
+	}
+	if vm.MemoryMB == 0 {
+		vm.MemoryMB = spec.MemoryMB
+	}
+	if vm.DiskGB == 0 {
+		vm.DiskGB = spec.DiskGB
+	}
+	if vm.Image == "" {
+		vm.Image = spec.Image
+	}
+	if vm.Description == "" {
+		vm.Description = spec.Description
+	}
+	if vm.Labels == nil {
+		vm.Labels = spec.Labels
+	}
+	if vm.UserData == "" {
+		vm.UserData = spec.UserData
+	}
+	if vm.SSHKeyNames == nil {
+		vm.SSHKeyNames = spec.SSHKeyNames
+	}
+	return &vm, nil
+}
+
+// UpdateVM applies changes to the mutable properties of an existing virtual machine
+func (c *Client) UpdateVM(ctx context.Context, name string, update VMUpdate) error {
+	defer c.vmList.invalidate()
+
+	return c.do(ctx, http.MethodPatch, "/virtualmachine/"+url.PathEscape(name), update, nil)
+}
+
+// DeleteVM deletes a virtual machine by name
+func (c *Client) DeleteVM(ctx context.Context, name string) error {
+	defer c.vmList.invalidate()
+
+	return c.do(ctx, http.MethodDelete, "/virtualmachine", VM{Name: name}, nil)
+}
+
+// GetVM retrieves a virtual machine by name (checks if it exists). It returns an error wrapping
+// ErrVMNotFound only when the API confirmed that the VM does not exist; any other error means
+// the existence of the VM could not be determined.
+//
+// Servers that support GET /virtualmachine/{name} are queried directly. For older servers that
+// only offer the list endpoint, GetVM falls back to scanning the full VM list.
+func (c *Client) GetVM(ctx context.Context, name string) (*VM, error) {
+	return c.lookupVM(ctx, name, c.ListVMs)
+}
+
+// lookupVM implements GetVM, falling back to scanning the VMs returned by list
+func (c *Client) lookupVM(
+	ctx context.Context,
+	name string,
+	list func(context.Context) ([]*VM, error),
+) (*VM, error) {
+	support := c.directLookup.Load()
+	if support == lookupUnsupported {
+		return findVMInList(ctx, name, list)
+	}
+
+	vm, err := c.getVMDirect(ctx, name)
+	switch {
+	case err == nil:
+		c.directLookup.Store(lookupSupported)
+		return vm, nil
+	case isUnsupportedEndpoint(err):
+		c.directLookup.Store(lookupUnsupported)
+		return findVMInList(ctx, name, list)
+	case IsNotFound(err) && support == lookupSupported:
+		return nil, fmt.Errorf("%w: %w", ErrVMNotFound, err)
+	case IsNotFound(err) && support == lookupUnknown:
+		// A 404 is ambiguous until the server is known to support direct lookups: either the VM
+		// does not exist or the endpoint does not. The list decides, and finding the VM there
+		// proves the endpoint is missing.
+		vm, err = findVMInList(ctx, name, list)
+		switch {
+		case err == nil:
+			c.directLookup.Store(lookupUnsupported)
+		case apiErrorStatus(err) == http.StatusNotFound:
+			// The list endpoint is missing as well, so nothing is known about the VM
+			return nil, fmt.Errorf("neither the VM nor the VM list endpoint was found, check the API "+
+				"endpoint: %w", err)
+		}
+		return vm, err
+	default:
 		return nil, err
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Printf("Warning: failed to close response body: %v", err)
+}
+
+// getVMDirect retrieves a virtual machine through the per-VM endpoint
+func (c *Client) getVMDirect(ctx context.Context, name string) (*VM, error) {
+	var vm VM
+	if err := c.do(ctx, http.MethodGet, "/virtualmachine/"+url.PathEscape(name), nil, &vm); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: %w", errUnexpectedLookupResponse, err)
 		}
-	}()
+		return nil, err
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("API error %d: failed to read response body: %w", resp.StatusCode, err)
+	if vm.Name != name {
+		return nil, fmt.Errorf("%w: expected VM '%s', got '%s'", errUnexpectedLookupResponse, name, vm.Name)
+	}
+
+	return &vm, nil
+}
+
+// findVMInList retrieves a virtual machine by scanning the full VM list returned by list
+func findVMInList(ctx context.Context, name string, list func(context.Context) ([]*VM, error)) (*VM, error) {
+	vms, err := list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vm := range vms {
+		if vm.Name == name {
+			return vm, nil
 		}
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
 
+	return nil, fmt.Errorf("%w: '%s' is not in the VM list. Please verify the VM name exists or check "+
+		"your API endpoint", ErrVMNotFound, name)
+}
+
+// isUnsupportedEndpoint reports whether err shows that the server does not implement the requested endpoint
+func isUnsupportedEndpoint(err error) bool {
+	if errors.Is(err, errUnexpectedLookupResponse) {
+		return true
+	}
+
+	status := apiErrorStatus(err)
+	return status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented
+}
+
+// ListVMs retrieves all virtual machines. Results are shared between concurrent callers and
+// reused for a short time; the cache is invalidated whenever a VM is created or deleted.
+func (c *Client) ListVMs(ctx context.Context) ([]*VM, error) {
+	return c.vmList.get(ctx, c.fetchVMs)
+}
+
+// fetchVMs retrieves all virtual machines from the API, bypassing the list cache
+func (c *Client) fetchVMs(ctx context.Context) ([]*VM, error) {
 	var vms []*VM
-	if err := json.NewDecoder(resp.Body).Decode(&vms); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.do(ctx, http.MethodGet, "/virtualmachine", nil, &vms); err != nil {
+		return nil, err
 	}
 
 	return vms, nil
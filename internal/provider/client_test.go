@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -76,7 +77,7 @@ func TestClient_CreateVM(t *testing.T) {
 			client := NewClient(server.URL, "test-api-key", 30)
 
 			// Test CreateVM
-			vm, err := client.CreateVM(context.Background(), tt.vmName)
+			vm, err := client.CreateVM(context.Background(), VM{Name: tt.vmName})
 
 			if tt.expectError {
 				if err == nil {
@@ -94,6 +95,42 @@ func TestClient_CreateVM(t *testing.T) {
 	}
 }
 
+func TestClient_CreateVM_SendsSpecification(t *testing.T) {
+	spec := VM{Name: "test-vm", CPU: 2, MemoryMB: 4096, DiskGB: 50, Image: "ubuntu-22.04"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		expected := map[string]interface{}{
+			"vmName": "test-vm", "cpu": float64(2), "memoryMb": float64(4096), "diskGb": float64(50),
+			"image": "ubuntu-22.04",
+		}
+		for key, value := range expected {
+			if body[key] != value {
+				t.Errorf("Expected %s=%v in request body, got %v", key, value, body[key])
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: "test-vm"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	vm, err := client.CreateVM(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(*vm, spec) {
+		t.Errorf("Expected created VM %+v, got %+v", spec, *vm)
+	}
+}
+
 func TestClient_DeleteVM(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -236,6 +273,7 @@ func TestClient_GetVM(t *testing.T) {
 		mockResponse   interface{}
 		mockStatusCode int
 		expectError    bool
+		expectNotFound bool
 	}{
 		{
 			name:   "VM found",
@@ -255,6 +293,15 @@ func TestClient_GetVM(t *testing.T) {
 			},
 			mockStatusCode: http.StatusOK,
 			expectError:    true,
+			expectNotFound: true,
+		},
+		{
+			name:           "list failure is not a confirmed absence",
+			vmName:         "test-vm",
+			mockResponse:   map[string]string{"error": "Internal server error"},
+			mockStatusCode: http.StatusInternalServerError,
+			expectError:    true,
+			expectNotFound: false,
 		},
 	}
 
@@ -265,8 +312,10 @@ func TestClient_GetVM(t *testing.T) {
 				if r.Method != http.MethodGet {
 					t.Fatalf("Expected GET request, got %s", r.Method)
 				}
+				// Simulate a server without the per-VM lookup endpoint
 				if r.URL.Path != vmPath {
-					t.Fatalf("Expected /virtualmachine path, got %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					return
 				}
 
 				w.Header().Set("Content-Type", "application/json")
@@ -285,6 +334,9 @@ func TestClient_GetVM(t *testing.T) {
 				if err == nil {
 					t.Errorf("Expected error, got nil")
 				}
+				if tt.expectNotFound != IsNotFound(err) {
+					t.Errorf("Expected IsNotFound=%t, got error: %v", tt.expectNotFound, err)
+				}
 			} else {
 				if err != nil {
 					t.Errorf("Expected no error, got %v", err)
@@ -297,6 +349,142 @@ func TestClient_GetVM(t *testing.T) {
 	}
 }
 
+func TestClient_GetVM_DirectLookup(t *testing.T) {
+	tests := []struct {
+		name            string
+		lookupStatus    int
+		lookupResponse  interface{}
+		listResponse    []*VM
+		vmNames         []string
+		expectNotFound  []bool
+		expectedLookups int
+		expectedLists   int
+	}{
+		{
+			name:            "server supports direct lookup",
+			lookupStatus:    http.StatusOK,
+			lookupResponse:  VM{Name: "test-vm"},
+			vmNames:         []string{"test-vm", "test-vm"},
+			expectNotFound:  []bool{false, false},
+			expectedLookups: 2,
+			expectedLists:   0,
+		},
+		{
+			name:            "method not allowed falls back to list",
+			lookupStatus:    http.StatusMethodNotAllowed,
+			listResponse:    []*VM{{Name: "test-vm"}},
+			vmNames:         []string{"test-vm", "test-vm"},
+			expectNotFound:  []bool{false, false},
+			expectedLookups: 1,
+			expectedLists:   2,
+		},
+		{
+			name:            "missing endpoint detected once VM is found in list",
+			lookupStatus:    http.StatusNotFound,
+			listResponse:    []*VM{{Name: "test-vm"}},
+			vmNames:         []string{"absent-vm", "test-vm", "test-vm"},
+			expectNotFound:  []bool{true, false, false},
+			expectedLookups: 2,
+			expectedLists:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookups, lists int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == vmPath {
+					lists++
+					w.WriteHeader(http.StatusOK)
+					_ = json.NewEncoder(w).Encode(tt.listResponse)
+					return
+				}
+
+				lookups++
+				w.WriteHeader(tt.lookupStatus)
+				if tt.lookupResponse != nil {
+					_ = json.NewEncoder(w).Encode(tt.lookupResponse)
+				}
+			}))
+			defer server.Close()
+
+			// Disable the list cache so every fallback reaches the server
+			client := NewClient(server.URL, "test-api-key", 30, WithListCacheTTL(0))
+
+			for i, name := range tt.vmNames {
+				vm, err := client.GetVM(context.Background(), name)
+				if tt.expectNotFound[i] {
+					if !IsNotFound(err) {
+						t.Errorf("GetVM(%s): expected not found error, got %v", name, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("GetVM(%s): expected no error, got %v", name, err)
+				}
+				if vm.Name != name {
+					t.Errorf("GetVM(%s): got VM %s", name, vm.Name)
+				}
+			}
+
+			if lookups != tt.expectedLookups {
+				t.Errorf("Expected %d direct lookups, got %d", tt.expectedLookups, lookups)
+			}
+			if lists != tt.expectedLists {
+				t.Errorf("Expected %d list calls, got %d", tt.expectedLists, lists)
+			}
+		})
+	}
+}
+
+func TestClient_GetVM_DirectLookupNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case vmPath + "/test-vm":
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(VM{Name: "test-vm"})
+		case vmPath:
+			t.Errorf("Unexpected list call once direct lookup is known to be supported")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "VM not found"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	if _, err := client.GetVM(context.Background(), "test-vm"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err := client.GetVM(context.Background(), "absent-vm")
+	if !errors.Is(err, ErrVMNotFound) {
+		t.Errorf("Expected ErrVMNotFound, got %v", err)
+	}
+}
+
+func TestClient_GetVM_ListNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Not found"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	_, err := client.GetVM(context.Background(), "test-vm")
+	if err == nil {
+		t.Fatal("Expected error when the list endpoint is not found, got none")
+	}
+	if errors.Is(err, ErrVMNotFound) {
+		t.Errorf("Expected a 404 from the list endpoint not to confirm the VM is absent, got %v", err)
+	}
+}
+
 func TestNewClientFromConfig(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -422,6 +610,148 @@ func TestNewClientFromConfig(t *testing.T) {
 	}
 }
 
+func TestNewClientFromConfig_RetryPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           DspcProviderModel
+		env              map[string]string
+		expectedPolicy   RetryPolicy
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name:           "defaults",
+			config:         DspcProviderModel{},
+			expectedPolicy: DefaultRetryPolicy(),
+		},
+		{
+			name: "explicit values",
+			config: DspcProviderModel{
+				MaxRetries:   types.Int64Value(5),
+				RetryWaitMin: types.Int64Value(2),
+				RetryWaitMax: types.Int64Value(10),
+			},
+			expectedPolicy: RetryPolicy{MaxRetries: 5, MinBackoff: 2 * time.Second, MaxBackoff: 10 * time.Second},
+		},
+		{
+			name: "retries disabled",
+			config: DspcProviderModel{
+				MaxRetries: types.Int64Value(0),
+			},
+			env: map[string]string{"DSPC_MAX_RETRIES": "7"},
+			expectedPolicy: RetryPolicy{
+				MaxRetries: 0,
+				MinBackoff: defaultRetryMinBackoff,
+				MaxBackoff: defaultRetryMaxBackoff,
+			},
+		},
+		{
+			name:   "environment variables",
+			config: DspcProviderModel{},
+			env: map[string]string{
+				"DSPC_MAX_RETRIES":    "1",
+				"DSPC_RETRY_WAIT_MIN": "3",
+				"DSPC_RETRY_WAIT_MAX": "4",
+			},
+			expectedPolicy: RetryPolicy{MaxRetries: 1, MinBackoff: 3 * time.Second, MaxBackoff: 4 * time.Second},
+		},
+		{
+			name:             "invalid environment variable",
+			config:           DspcProviderModel{},
+			env:              map[string]string{"DSPC_MAX_RETRIES": "many"},
+			expectError:      true,
+			expectedErrorMsg: "DSPC_MAX_RETRIES",
+		},
+		{
+			name: "negative retries",
+			config: DspcProviderModel{
+				MaxRetries: types.Int64Value(-1),
+			},
+			expectError:      true,
+			expectedErrorMsg: "max_retries must not be negative",
+		},
+		{
+			name: "min wait greater than max wait",
+			config: DspcProviderModel{
+				RetryWaitMin: types.Int64Value(60),
+				RetryWaitMax: types.Int64Value(10),
+			},
+			expectError:      true,
+			expectedErrorMsg: "must not be greater than retry_wait_max",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			tt.config.Endpoint = types.StringValue("https://api.example.com")
+			tt.config.APIKey = types.StringValue("test-key")
+
+			client, err := NewClientFromConfig(tt.config)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				} else if !strings.Contains(err.Error(), tt.expectedErrorMsg) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if client.retry != tt.expectedPolicy {
+				t.Errorf("Expected retry policy %+v, got %+v", tt.expectedPolicy, client.retry)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig_ListCacheTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      DspcProviderModel
+		env         string
+		expectedTTL time.Duration
+		expectError bool
+	}{
+		{name: "default", expectedTTL: defaultListCacheTTL},
+		{name: "explicit", config: DspcProviderModel{ListCacheTTL: types.Int64Value(60)}, expectedTTL: time.Minute},
+		{name: "disabled", config: DspcProviderModel{ListCacheTTL: types.Int64Value(0)}, env: "30", expectedTTL: 0},
+		{name: "environment variable", env: "10", expectedTTL: 10 * time.Second},
+		{name: "negative", config: DspcProviderModel{ListCacheTTL: types.Int64Value(-5)}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("DSPC_LIST_CACHE_TTL", tt.env)
+			}
+
+			tt.config.Endpoint = types.StringValue("https://api.example.com")
+			tt.config.APIKey = types.StringValue("test-key")
+
+			client, err := NewClientFromConfig(tt.config)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if client.vmList.ttl != tt.expectedTTL {
+				t.Errorf("Expected list cache TTL %s, got %s", tt.expectedTTL, client.vmList.ttl)
+			}
+		})
+	}
+}
+
 func TestClient_ContextTimeout(t *testing.T) {
 	// Create a server that delays response
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
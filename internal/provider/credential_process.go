@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialProcessTimeout bounds a single run of the credential process
+const credentialProcessTimeout = time.Minute
+
+// credentialProcessOutput is the JSON document a credential process writes to stdout
+type credentialProcessOutput struct {
+	Token string `json:"token"`
+	// ExpiresAt is an RFC 3339 timestamp. Tokens without an expiry are reused until the API
+	// rejects them.
+	ExpiresAt string `json:"expires_at"`
+}
+
+// CredentialProcess obtains API tokens by running an external command, so that no secret
+// has to be stored in the Terraform configuration or environment. The command is re-run
+// when the token is about to expire or is rejected by the API.
+type CredentialProcess struct {
+	command string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewCredentialProcess creates a token provider that runs command through the system shell
+func NewCredentialProcess(command string) *CredentialProcess {
+	return &CredentialProcess{command: command}
+}
+
+// Token returns the cached token, or runs the credential process when none is cached or
+// the cached token is about to expire
+func (p *CredentialProcess) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && (p.expiry.IsZero() || time.Until(p.expiry) > tokenExpiryDelta) {
+		return p.token, nil
+	}
+
+	token, expiry, err := p.run(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	p.token, p.expiry = token, expiry
+	return token, nil
+}
+
+// Invalidate discards the cached token so the next call to Token runs the process again
+func (p *CredentialProcess) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.token = ""
+	p.expiry = time.Time{}
+}
+
+// run executes the credential process and parses its output
+func (p *CredentialProcess) run(ctx context.Context) (string, time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", p.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", p.command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// stderr is included for troubleshooting; stdout may contain a token and is never echoed
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", time.Time{}, fmt.Errorf("credential_process failed: %w: %s", err, detail)
+		}
+		return "", time.Time{}, fmt.Errorf("credential_process failed: %w", err)
+	}
+
+	var output credentialProcessOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return "", time.Time{}, fmt.Errorf("credential_process output is not valid JSON: %w", err)
+	}
+	if output.Token == "" {
+		return "", time.Time{}, fmt.Errorf("credential_process output does not contain a token")
+	}
+
+	var expiry time.Time
+	if output.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, output.ExpiresAt)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("credential_process returned an invalid expires_at: %w", err)
+		}
+		expiry = parsed
+	}
+
+	return output.Token, expiry, nil
+}
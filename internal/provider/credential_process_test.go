@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// credentialCommand returns a shell command that records each run in a file and prints output
+func credentialCommand(t *testing.T, output string) (command, runsFile string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use a POSIX shell")
+	}
+
+	runsFile = filepath.Join(t.TempDir(), "runs")
+	return fmt.Sprintf("echo run >> '%s'; printf '%%s' '%s'", runsFile, output), runsFile
+}
+
+// credentialRuns returns how often the credential process ran
+func credentialRuns(t *testing.T, runsFile string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(runsFile)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("Failed to read runs file: %v", err)
+	}
+	return strings.Count(string(data), "run")
+}
+
+func TestCredentialProcess_Token(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		expectError  string
+		expectedRuns int
+	}{
+		{
+			name:         "token without expiry is cached",
+			output:       `{"token": "process-token"}`,
+			expectedRuns: 1,
+		},
+		{
+			name: "token with distant expiry is cached",
+			output: fmt.Sprintf(`{"token": "process-token", "expires_at": "%s"}`,
+				time.Now().Add(time.Hour).Format(time.RFC3339)),
+			expectedRuns: 1,
+		},
+		{
+			name: "expiring token is fetched again",
+			output: fmt.Sprintf(`{"token": "process-token", "expires_at": "%s"}`,
+				time.Now().Add(time.Second).Format(time.RFC3339)),
+			expectedRuns: 2,
+		},
+		{
+			name:        "invalid JSON",
+			output:      `process-token`,
+			expectError: "not valid JSON",
+		},
+		{
+			name:        "missing token",
+			output:      `{"expires_at": "2030-01-01T00:00:00Z"}`,
+			expectError: "does not contain a token",
+		},
+		{
+			name:        "invalid expiry",
+			output:      `{"token": "process-token", "expires_at": "tomorrow"}`,
+			expectError: "invalid expires_at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, runsFile := credentialCommand(t, tt.output)
+			process := NewCredentialProcess(command)
+
+			for i := 0; i < 2; i++ {
+				token, err := process.Token(context.Background())
+				if tt.expectError != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+						t.Fatalf("Expected error containing %q, got %v", tt.expectError, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if token != "process-token" {
+					t.Errorf("Expected process-token, got %s", token)
+				}
+			}
+
+			if got := credentialRuns(t, runsFile); got != tt.expectedRuns {
+				t.Errorf("Expected %d runs, got %d", tt.expectedRuns, got)
+			}
+		})
+	}
+}
+
+func TestCredentialProcess_CommandFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use a POSIX shell")
+	}
+
+	process := NewCredentialProcess("echo 'not logged in' >&2; exit 3")
+
+	_, err := process.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not logged in") {
+		t.Errorf("Expected error with stderr output, got %v", err)
+	}
+}
+
+func TestCredentialProcess_RerunOnUnauthorized(t *testing.T) {
+	command, runsFile := credentialCommand(t, `{"token": "process-token"}`)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer process-token" {
+			t.Errorf("Expected Authorization: Bearer process-token, got %s", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]*VM{})
+	}))
+	defer server.Close()
+
+	t.Setenv("DSPC_API_KEY", "")
+	client, err := NewClientFromConfig(DspcProviderModel{
+		Endpoint:          types.StringValue(server.URL),
+		CredentialProcess: types.StringValue(command),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.ListVMs(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := credentialRuns(t, runsFile); got != 2 {
+		t.Errorf("Expected the process to run again after HTTP 401, got %d runs", got)
+	}
+}
+
+func TestNewClientFromConfig_CredentialProcessConflictsWithOAuth2(t *testing.T) {
+	_, err := NewClientFromConfig(DspcProviderModel{
+		Endpoint:          types.StringValue("http://localhost:8080"),
+		CredentialProcess: types.StringValue("get-dspc-token"),
+		OAuth2: &OAuth2Model{
+			TokenURL:     types.StringValue("https://issuer.example.com/token"),
+			ClientID:     types.StringValue("terraform"),
+			ClientSecret: types.StringValue("s3cret"),
+			Scopes:       types.ListNull(types.StringType),
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+}
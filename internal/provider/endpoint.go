@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// validateEndpoint checks that endpoint is an absolute http or https URL with a host. A path
+// prefix such as https://gw.example.com/dspc/v1 is allowed; query strings and fragments are not.
+func validateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case parsed.Scheme != "http" && parsed.Scheme != "https":
+		return fmt.Errorf("the URL must start with http:// or https://")
+	case parsed.Host == "" || parsed.Hostname() == "":
+		return fmt.Errorf("the URL must include a host name")
+	case parsed.User != nil:
+		return fmt.Errorf("credentials must not be part of the URL; use api_key or another authentication setting")
+	case parsed.RawQuery != "" || parsed.ForceQuery:
+		return fmt.Errorf("the URL must not contain a query string")
+	case parsed.Fragment != "":
+		return fmt.Errorf("the URL must not contain a fragment")
+	}
+
+	return nil
+}
+
+// resolveURL appends path, which may carry a query string, to the endpoint. Unlike
+// url.ResolveReference, a path prefix in the endpoint is preserved, so an endpoint of
+// https://gw.example.com/dspc/v1 and a path of /virtualmachine give
+// https://gw.example.com/dspc/v1/virtualmachine.
+func resolveURL(endpoint, path string) (*url.URL, error) {
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+
+	ref, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+
+	resolved := *base
+	resolved.Path = strings.TrimSuffix(base.Path, "/") + ref.Path
+	resolved.RawPath = ""
+	if base.RawPath != "" || ref.RawPath != "" {
+		// Keep escaped characters such as %2F in VM names as they were
+		resolved.RawPath = strings.TrimSuffix(base.EscapedPath(), "/") + ref.EscapedPath()
+	}
+	resolved.RawQuery = ref.RawQuery
+	resolved.Fragment = ""
+
+	return &resolved, nil
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// ErrVMNotFound is returned when the API was reached successfully but the requested VM does not exist
+var ErrVMNotFound = errors.New("VM not found")
+
+// ErrSSHKeyNotFound is returned when the API was reached successfully but the requested SSH key does not exist
+var ErrSSHKeyNotFound = errors.New("SSH key not found")
+
+// maxErrorBodySize limits how much of an error response body is read into an APIError
+const maxErrorBodySize = 64 * 1024
+
+// APIError describes a non-successful response from the DSPC API
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Method and Path identify the request that failed
+	Method string
+	Path   string
+	// RequestID is the request identifier reported by the API, or otherwise the one sent by the client
+	RequestID string
+	// Code and Message are parsed from a JSON error body, if present
+	Code    string
+	Message string
+	// Body is the raw response body
+	Body string
+}
+
+// apiErrorBody is the JSON error format returned by the DSPC API
+type apiErrorBody struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "API error %d", e.StatusCode)
+	if e.Method != "" {
+		fmt.Fprintf(&b, " (%s %s)", e.Method, e.Path)
+	}
+
+	switch {
+	case e.Message != "":
+		fmt.Fprintf(&b, ": %s", e.Message)
+	case e.Body != "":
+		fmt.Fprintf(&b, ": %s", e.Body)
+	default:
+		fmt.Fprintf(&b, ": %s", http.StatusText(e.StatusCode))
+	}
+
+	if e.Code != "" {
+		fmt.Fprintf(&b, " [%s]", e.Code)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID: %s)", e.RequestID)
+	}
+
+	return b.String()
+}
+
+// newAPIError builds an APIError from a non-successful response, consuming its body
+func newAPIError(resp *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(requestIDHeader),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.Path
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = resp.Request.Header.Get(requestIDHeader)
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return fmt.Errorf("API error %d: failed to read response body: %w", resp.StatusCode, err)
+	}
+	apiErr.Body = strings.TrimSpace(string(body))
+
+	var parsed apiErrorBody
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		apiErr.Code = parsed.Code
+		apiErr.Message = parsed.Error
+		if apiErr.Message == "" {
+			apiErr.Message = parsed.Message
+		}
+	}
+
+	return apiErr
+}
+
+// apiErrorStatus returns the status code of an APIError wrapped in err, or 0 if there is none
+func apiErrorStatus(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an API error with status 404 or confirms that a VM does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrVMNotFound) || apiErrorStatus(err) == http.StatusNotFound
+}
+
+// IsConflict reports whether err is an API error with status 409
+func IsConflict(err error) bool {
+	return apiErrorStatus(err) == http.StatusConflict
+}
+
+// IsUnauthorized reports whether err is an API error with status 401
+func IsUnauthorized(err error) bool {
+	return apiErrorStatus(err) == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether err is an API error with status 403
+func IsForbidden(err error) bool {
+	return apiErrorStatus(err) == http.StatusForbidden
+}
+
+// IsRetryable reports whether err is an API error indicating a transient failure
+func IsRetryable(err error) bool {
+	return isRetryableStatus(apiErrorStatus(err))
+}
+
+// addAPIError adds an error diagnostic for a failed API operation, refining the summary
+// and adding guidance based on the kind of API error
+func addAPIError(diags *diag.Diagnostics, summary, action string, err error) {
+	var hint string
+
+	switch {
+	case IsUnauthorized(err):
+		summary += ": authentication failed"
+		hint = "The DSPC API rejected the provided credentials. Verify api_key (DSPC_API_KEY), " +
+			"credential_process or the oauth2 block."
+	case IsForbidden(err):
+		summary += ": permission denied"
+		hint = "The credentials were accepted but are not allowed to perform this operation."
+	case IsNotFound(err):
+		summary += ": not found"
+		hint = "The requested object does not exist. Please verify the name or check your API endpoint."
+	case IsConflict(err):
+		summary += ": conflict"
+		hint = "The request conflicts with the current state of the platform, for example an object " +
+			"with the same name already exists."
+	case IsRetryable(err):
+		summary += ": service unavailable"
+		hint = "The DSPC API reported a temporary failure and all retries were used. Try again later " +
+			"or increase max_retries."
+	}
+
+	detail := fmt.Sprintf("Could not %s: %s", action, err.Error())
+	if hint != "" {
+		detail += "\n\n" + hint
+	}
+
+	diags.AddError(summary, detail)
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestClient_ReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-ID", "req-123")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error": "VM already exists", "code": "vm_exists"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	_, err := client.CreateVM(context.Background(), VM{Name: "test-vm"})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+
+	if apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, apiErr.StatusCode)
+	}
+	if apiErr.Method != http.MethodPost || apiErr.Path != vmPath {
+		t.Errorf("Expected POST %s, got %s %s", vmPath, apiErr.Method, apiErr.Path)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("Expected request ID req-123, got %q", apiErr.RequestID)
+	}
+	if apiErr.Message != "VM already exists" || apiErr.Code != "vm_exists" {
+		t.Errorf("Expected parsed message and code, got %q and %q", apiErr.Message, apiErr.Code)
+	}
+	if !IsConflict(err) {
+		t.Error("Expected IsConflict to be true")
+	}
+
+	expected := "API error 409 (POST /virtualmachine): VM already exists [vm_exists] (request ID: req-123)"
+	if err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err.Error())
+	}
+}
+
+func TestAPIError_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *APIError
+		expected string
+	}{
+		{
+			name:     "message field",
+			err:      &APIError{StatusCode: 404, Method: "GET", Path: "/virtualmachine", Message: "VM not found"},
+			expected: "API error 404 (GET /virtualmachine): VM not found",
+		},
+		{
+			name:     "non-JSON body",
+			err:      &APIError{StatusCode: 502, Method: "GET", Path: "/virtualmachine", Body: "Bad Gateway"},
+			expected: "API error 502 (GET /virtualmachine): Bad Gateway",
+		},
+		{
+			name:     "empty body",
+			err:      &APIError{StatusCode: 503},
+			expected: "API error 503: Service Unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	tests := []struct {
+		status       int
+		notFound     bool
+		conflict     bool
+		unauthorized bool
+		forbidden    bool
+		retryable    bool
+	}{
+		{status: http.StatusNotFound, notFound: true},
+		{status: http.StatusConflict, conflict: true},
+		{status: http.StatusUnauthorized, unauthorized: true},
+		{status: http.StatusForbidden, forbidden: true},
+		{status: http.StatusTooManyRequests, retryable: true},
+		{status: http.StatusServiceUnavailable, retryable: true},
+		{status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			// Helpers must see through wrapping
+			err := fmt.Errorf("wrapped: %w", &APIError{StatusCode: tt.status})
+
+			if IsNotFound(err) != tt.notFound {
+				t.Errorf("IsNotFound: expected %t", tt.notFound)
+			}
+			if IsConflict(err) != tt.conflict {
+				t.Errorf("IsConflict: expected %t", tt.conflict)
+			}
+			if IsUnauthorized(err) != tt.unauthorized {
+				t.Errorf("IsUnauthorized: expected %t", tt.unauthorized)
+			}
+			if IsForbidden(err) != tt.forbidden {
+				t.Errorf("IsForbidden: expected %t", tt.forbidden)
+			}
+			if IsRetryable(err) != tt.retryable {
+				t.Errorf("IsRetryable: expected %t", tt.retryable)
+			}
+		})
+	}
+
+	if IsNotFound(errors.New("plain error")) {
+		t.Error("Expected IsNotFound to be false for a non-API error")
+	}
+}
+
+func TestAddAPIError(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedSummary string
+		expectedDetail  string
+	}{
+		{
+			name:            "unauthorized",
+			err:             &APIError{StatusCode: http.StatusUnauthorized},
+			expectedSummary: "Error creating VM: authentication failed",
+			expectedDetail:  "credential_process or the oauth2 block",
+		},
+		{
+			name:            "conflict",
+			err:             &APIError{StatusCode: http.StatusConflict, Message: "VM already exists"},
+			expectedSummary: "Error creating VM: conflict",
+			expectedDetail:  "Could not create VM: API error 409: VM already exists",
+		},
+		{
+			name:            "retryable",
+			err:             &APIError{StatusCode: http.StatusBadGateway},
+			expectedSummary: "Error creating VM: service unavailable",
+			expectedDetail:  "max_retries",
+		},
+		{
+			name:            "other error",
+			err:             errors.New("failed to make request: connection refused"),
+			expectedSummary: "Error creating VM",
+			expectedDetail:  "Could not create VM: failed to make request: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addAPIError(&diags, "Error creating VM", "create VM", tt.err)
+
+			if len(diags) != 1 {
+				t.Fatalf("Expected 1 diagnostic, got %d", len(diags))
+			}
+			if diags[0].Severity() != diag.SeverityError {
+				t.Errorf("Expected error severity, got %s", diags[0].Severity())
+			}
+			if diags[0].Summary() != tt.expectedSummary {
+				t.Errorf("Expected summary %q, got %q", tt.expectedSummary, diags[0].Summary())
+			}
+			if !strings.Contains(diags[0].Detail(), tt.expectedDetail) {
+				t.Errorf("Expected detail to contain %q, got %q", tt.expectedDetail, diags[0].Detail())
+			}
+		})
+	}
+}
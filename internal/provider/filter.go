@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// VMFilter selects a subset of the VM list. Empty fields match every VM.
+type VMFilter struct {
+	NamePrefix string
+	// NameRegex is only applied client-side since the API has no equivalent query parameter
+	NameRegex *regexp.Regexp
+	// Labels must all be present on a VM with the given values
+	Labels map[string]string
+	Status string
+}
+
+// IsEmpty reports whether the filter matches every VM
+func (f VMFilter) IsEmpty() bool {
+	return f.NamePrefix == "" && f.NameRegex == nil && len(f.Labels) == 0 && f.Status == ""
+}
+
+// query encodes the server-side part of the filter as query parameters. Labels are sent as
+// repeated label=key=value parameters in key order so requests are reproducible.
+func (f VMFilter) query() url.Values {
+	query := url.Values{}
+	if f.NamePrefix != "" {
+		query.Set("namePrefix", f.NamePrefix)
+	}
+	if f.Status != "" {
+		query.Set("status", f.Status)
+	}
+
+	keys := make([]string, 0, len(f.Labels))
+	for key := range f.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		query.Add("label", key+"="+f.Labels[key])
+	}
+
+	return query
+}
+
+// Matches reports whether vm satisfies every condition of the filter
+func (f VMFilter) Matches(vm *VM) bool {
+	if vm == nil {
+		return false
+	}
+	if f.NamePrefix != "" && !strings.HasPrefix(vm.Name, f.NamePrefix) {
+		return false
+	}
+	if f.NameRegex != nil && !f.NameRegex.MatchString(vm.Name) {
+		return false
+	}
+	if f.Status != "" && !strings.EqualFold(vm.Status, f.Status) {
+		return false
+	}
+	for key, value := range f.Labels {
+		if actual, ok := vm.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ListVMsFiltered retrieves the VMs matching filter. The filter is sent to the API as query
+// parameters and applied again to the response, so servers that ignore the parameters return
+// the same result. Filtered requests bypass the VM list cache.
+func (c *Client) ListVMsFiltered(ctx context.Context, filter VMFilter) ([]*VM, error) {
+	if filter.IsEmpty() {
+		return c.ListVMs(ctx)
+	}
+
+	path := "/virtualmachine"
+	if query := filter.query(); len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var vms []*VM
+	if err := c.do(ctx, http.MethodGet, path, nil, &vms); err != nil {
+		return nil, err
+	}
+
+	matched := make([]*VM, 0, len(vms))
+	for _, vm := range vms {
+		if filter.Matches(vm) {
+			matched = append(matched, vm)
+		}
+	}
+
+	return matched, nil
+}
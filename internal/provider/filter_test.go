@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestVMFilter_Matches(t *testing.T) {
+	vm := &VM{Name: "web-01", Status: VMStatusRunning, Labels: map[string]string{"team": "web", "env": "prod"}}
+
+	tests := []struct {
+		name     string
+		filter   VMFilter
+		expected bool
+	}{
+		{name: "empty filter", filter: VMFilter{}, expected: true},
+		{name: "matching prefix", filter: VMFilter{NamePrefix: "web-"}, expected: true},
+		{name: "other prefix", filter: VMFilter{NamePrefix: "db-"}, expected: false},
+		{name: "matching regex", filter: VMFilter{NameRegex: regexp.MustCompile(`^web-\d+$`)}, expected: true},
+		{name: "other regex", filter: VMFilter{NameRegex: regexp.MustCompile(`^db`)}, expected: false},
+		{name: "status is case insensitive", filter: VMFilter{Status: "RUNNING"}, expected: true},
+		{name: "other status", filter: VMFilter{Status: "stopped"}, expected: false},
+		{name: "matching labels", filter: VMFilter{Labels: map[string]string{"team": "web"}}, expected: true},
+		{name: "different label value", filter: VMFilter{Labels: map[string]string{"env": "dev"}}, expected: false},
+		{name: "missing label", filter: VMFilter{Labels: map[string]string{"owner": "ops"}}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(vm); got != tt.expected {
+				t.Errorf("Expected Matches=%t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClient_ListVMsFiltered(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		// The server ignores the filter and returns every VM
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*VM{
+			{Name: "web-01", Status: VMStatusRunning, Labels: map[string]string{"team": "web"}},
+			{Name: "web-02", Status: "provisioning", Labels: map[string]string{"team": "web"}},
+			{Name: "db-01", Status: VMStatusRunning, Labels: map[string]string{"team": "db"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	vms, err := client.ListVMsFiltered(context.Background(), VMFilter{
+		NamePrefix: "web-",
+		Status:     VMStatusRunning,
+		Labels:     map[string]string{"team": "web", "env": "prod"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedQuery := "label=env%3Dprod&label=team%3Dweb&namePrefix=web-&status=running"
+	if query != expectedQuery {
+		t.Errorf("Expected query %q, got %q", expectedQuery, query)
+	}
+
+	// No VM has the env label, so client-side filtering must drop all of them
+	if len(vms) != 0 {
+		t.Errorf("Expected no VMs, got %d", len(vms))
+	}
+
+	vms, err = client.ListVMsFiltered(context.Background(), VMFilter{
+		NamePrefix: "web-",
+		Status:     VMStatusRunning,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(vms) != 1 || vms[0].Name != "web-01" {
+		t.Errorf("Expected only web-01, got %+v", vms)
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// defaultUserAgent identifies the provider when its version is not known
+const defaultUserAgent = "terraform-provider-dspc"
+
+// requestIDHeader carries the identifier used to correlate a request with the API server logs
+const requestIDHeader = "X-Request-ID"
+
+// WithUserAgent sets the User-Agent header sent with every API request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// userAgent builds the User-Agent for the given provider and Terraform versions, for example
+// "terraform-provider-dspc/1.2.0 terraform/1.9.5". Unknown versions are left out.
+func userAgent(providerVersion, terraformVersion string) string {
+	ua := defaultUserAgent
+	if providerVersion != "" {
+		ua += "/" + providerVersion
+	}
+	if terraformVersion != "" {
+		ua += " terraform/" + terraformVersion
+	}
+	return ua
+}
+
+// newRequestID returns a random UUID (version 4) identifying one API call
+func newRequestID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate request ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		name             string
+		providerVersion  string
+		terraformVersion string
+		expected         string
+	}{
+		{
+			name:             "both versions",
+			providerVersion:  "1.2.0",
+			terraformVersion: "1.9.5",
+			expected:         "terraform-provider-dspc/1.2.0 terraform/1.9.5",
+		},
+		{
+			name:            "unknown Terraform version",
+			providerVersion: "dev",
+			expected:        "terraform-provider-dspc/dev",
+		},
+		{
+			name:     "no versions",
+			expected: "terraform-provider-dspc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := userAgent(tt.providerVersion, tt.terraformVersion); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestClient_RequestHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var userAgents, requestIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		requestIDs = append(requestIDs, r.Header.Get(requestIDHeader))
+		attempt := len(requestIDs)
+		mu.Unlock()
+
+		switch attempt {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30,
+		WithRetryPolicy(testRetryPolicy),
+		WithUserAgent("terraform-provider-dspc/1.2.0 terraform/1.9.5"),
+	)
+
+	if err := client.do(context.Background(), http.MethodGet, vmPath, nil, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	err := client.do(context.Background(), http.MethodPost, vmPath, nil, nil)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if len(requestIDs) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requestIDs))
+	}
+	for _, ua := range userAgents {
+		if ua != "terraform-provider-dspc/1.2.0 terraform/1.9.5" {
+			t.Errorf("Expected provider User-Agent, got %q", ua)
+		}
+	}
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuid.MatchString(requestIDs[0]) {
+		t.Errorf("Expected request ID to be a UUID, got %q", requestIDs[0])
+	}
+	if requestIDs[0] != requestIDs[1] {
+		t.Errorf("Expected retry to reuse request ID %s, got %s", requestIDs[0], requestIDs[1])
+	}
+	if requestIDs[2] == requestIDs[0] {
+		t.Error("Expected a new request ID for a separate call")
+	}
+
+	// The server did not echo the ID, so the error reports the one sent by the client
+	if !strings.Contains(err.Error(), "request ID: "+requestIDs[2]) {
+		t.Errorf("Expected error to contain request ID %s, got %v", requestIDs[2], err)
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// CheckConnection verifies that the API is reachable and accepts the client's credentials.
+// Servers that advertise their capabilities are probed with GET /capabilities, a small response
+// that is needed for planning anyway. Servers implementing only the minimal API have no cheaper
+// authenticated endpoint than the VM list, so they are checked by listing VMs; the list is cached
+// like any other, so the first refresh of a plan reuses it rather than requesting it again.
+func (c *Client) CheckConnection(ctx context.Context) error {
+	capabilities, err := c.Capabilities(ctx)
+
+	var urlErr *url.Error
+	switch {
+	case err == nil && capabilities != nil:
+		return nil
+	case IsUnauthorized(err) || errors.As(err, &urlErr):
+		// The credentials were rejected or no response was received at all
+		return err
+	}
+
+	_, err = c.ListVMs(ctx)
+	return err
+}
+
+// skipCredentialsValidationFromConfig resolves skip_credentials_validation from provider
+// configuration with environment variable and profile fallbacks
+func skipCredentialsValidationFromConfig(config DspcProviderModel) (bool, error) {
+	sources, err := loadConfigSources(config)
+	if err != nil {
+		return false, err
+	}
+
+	skip, _, err := sources.boolSetting(config.SkipCredentialsValidation, "DSPC_SKIP_CREDENTIALS_VALIDATION")
+	return skip, err
+}
+
+// addConnectionError adds an error diagnostic for a failed connection check, naming the most
+// likely cause: an unreachable host, a TLS failure or rejected credentials
+func addConnectionError(diags *diag.Diagnostics, endpoint string, err error) {
+	var summary, hint string
+
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var recordHeaderErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+
+	switch {
+	case IsUnauthorized(err):
+		summary = "DSPC API Authentication Failed"
+		hint = "The DSPC API rejected the provided credentials. Verify api_key (DSPC_API_KEY), " +
+			"credential_process or the oauth2 block."
+	case IsForbidden(err):
+		summary = "DSPC API Permission Denied"
+		hint = "The credentials were accepted but are not allowed to list virtual machines."
+	case errors.As(err, &certErr) || errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr):
+		summary = "DSPC API TLS Verification Failed"
+		hint = "The API server certificate could not be verified. If it is signed by an internal CA, " +
+			"configure ca_cert_file or ca_cert_pem."
+	case errors.As(err, &recordHeaderErr) || strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		summary = "DSPC API TLS Handshake Failed"
+		hint = "The server did not respond with TLS. Check whether the endpoint should use http:// " +
+			"instead of https://."
+	case errors.As(err, &alertErr):
+		summary = "DSPC API TLS Handshake Failed"
+		hint = "The server rejected the TLS connection. If it requires a client certificate, configure " +
+			"client_cert and client_key."
+	case errors.As(err, &dnsErr) || errors.As(err, &opErr):
+		summary = "DSPC API Unreachable"
+		hint = "Check that the endpoint is correct and reachable from this machine, including any " +
+			"proxy settings."
+	default:
+		summary = "DSPC API Check Failed"
+	}
+
+	detail := fmt.Sprintf("Could not connect to the DSPC API at %s: %s", endpoint, err.Error())
+	if hint != "" {
+		detail += "\n\n" + hint
+	}
+	detail += "\n\nSet skip_credentials_validation to true to skip this check."
+
+	diags.AddError(summary, detail)
+}
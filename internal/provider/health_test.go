@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// configureProvider runs Configure on a new provider with the given configuration
+func configureProvider(t *testing.T, config DspcProviderModel) *provider.ConfigureResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	p := &DspcProvider{version: "test"}
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, &config); diags.HasError() {
+		t.Fatalf("Failed to build provider config: %v", diags)
+	}
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config:           tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw},
+		TerraformVersion: "1.9.5",
+	}, resp)
+	return resp
+}
+
+func TestProviderConfigure_ConnectionCheck(t *testing.T) {
+	okServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer okServer.Close()
+
+	unauthorizedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorizedServer.Close()
+
+	forbiddenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer forbiddenServer.Close()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer tlsServer.Close()
+
+	closedServer := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	closedURL := closedServer.URL
+	closedServer.Close()
+
+	tests := []struct {
+		name            string
+		endpoint        string
+		skip            bool
+		expectedSummary string
+	}{
+		{name: "reachable", endpoint: okServer.URL},
+		{name: "unauthorized", endpoint: unauthorizedServer.URL, expectedSummary: "DSPC API Authentication Failed"},
+		{name: "forbidden", endpoint: forbiddenServer.URL, expectedSummary: "DSPC API Permission Denied"},
+		{name: "untrusted certificate", endpoint: tlsServer.URL, expectedSummary: "DSPC API TLS Verification Failed"},
+		{name: "plain HTTP server", endpoint: strings.Replace(okServer.URL, "http://", "https://", 1),
+			expectedSummary: "DSPC API TLS Handshake Failed"},
+		{name: "unreachable", endpoint: closedURL, expectedSummary: "DSPC API Unreachable"},
+		{name: "skipped", endpoint: closedURL, skip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DSPC_CONFIG_FILE", filepath.Join(t.TempDir(), "missing"))
+			t.Setenv("DSPC_SKIP_CREDENTIALS_VALIDATION", "")
+
+			resp := configureProvider(t, DspcProviderModel{
+				Endpoint:                  types.StringValue(tt.endpoint),
+				APIKey:                    types.StringValue("test-key"),
+				MaxRetries:                types.Int64Value(0),
+				SkipCredentialsValidation: types.BoolValue(tt.skip),
+			})
+
+			if tt.expectedSummary == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("Expected no error, got %v", resp.Diagnostics)
+				}
+				if resp.ResourceData == nil {
+					t.Error("Expected client to be configured")
+				}
+				return
+			}
+
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("Expected error, got none")
+			}
+			if summary := resp.Diagnostics.Errors()[0].Summary(); summary != tt.expectedSummary {
+				t.Errorf("Expected summary %q, got %q: %s", tt.expectedSummary, summary,
+					resp.Diagnostics.Errors()[0].Detail())
+			}
+			if resp.ResourceData != nil {
+				t.Error("Expected no client to be configured")
+			}
+		})
+	}
+}
+
+func TestClient_CheckConnection(t *testing.T) {
+	tests := []struct {
+		name              string
+		capabilities      string
+		expectedListCalls int32
+	}{
+		{name: "capabilities advertised", capabilities: `{"apiVersion": "2.2"}`},
+		{name: "minimal API", expectedListCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var listCalls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/capabilities" && tt.capabilities != "":
+					_, _ = w.Write([]byte(tt.capabilities))
+				case r.URL.Path == vmPath:
+					listCalls.Add(1)
+					_, _ = w.Write([]byte(`[]`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-api-key", 30)
+
+			if err := client.CheckConnection(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := listCalls.Load(); got != tt.expectedListCalls {
+				t.Errorf("Expected %d list requests, got %d", tt.expectedListCalls, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// idempotencyKeyHeader carries the key that lets the API recognize repeated create requests
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyContextKey stores the idempotent operation of the current request in a context
+type idempotencyKeyContextKey struct{}
+
+// idempotentOperation describes an operation whose requests all carry the same idempotency key
+type idempotentOperation struct {
+	key string
+	// retried is set once a request of the operation is sent again after a failed attempt,
+	// which the server may have processed before the failure
+	retried bool
+}
+
+// withIdempotencyKey marks all requests made with ctx as attempts of the same operation.
+// makeRequest sends the key in the Idempotency-Key header, retries these requests like
+// idempotent ones and records retries in op.
+func withIdempotencyKey(ctx context.Context, op *idempotentOperation) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, op)
+}
+
+// idempotentOperationFromContext returns the idempotent operation stored in ctx, if any
+func idempotentOperationFromContext(ctx context.Context) *idempotentOperation {
+	op, _ := ctx.Value(idempotencyKeyContextKey{}).(*idempotentOperation)
+	return op
+}
+
+// idempotencyKeyFor derives the idempotency key of a create from the requested VM. The key is
+// the same for every create of the same specification, so a later run repeating a create whose
+// response was lost sends the key of the create that made the VM.
+func idempotencyKeyFor(spec VM) (string, error) {
+	// Labels are marshaled with sorted keys and SSH key names are sorted by the resource, so
+	// equal specifications produce equal bodies
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive idempotency key: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sameStringSet reports whether a and b contain the same strings, regardless of order
+func sameStringSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// reportsSpec reports whether the VM reports enough of its specification to be compared with the
+// specification it may have been created from. Servers implementing only the minimal API return
+// just the name, which says nothing about who created the VM.
+func (vm *VM) reportsSpec() bool {
+	return vm.CPU != 0 && vm.MemoryMB != 0 && vm.DiskGB != 0
+}
+
+// matchesSpec reports whether an existing VM reports every field of the specification it may
+// have been created from with the requested value. A requested field the API does not report
+// counts as a mismatch, since it cannot show that the VM was created from spec.
+func (vm *VM) matchesSpec(spec VM) bool {
+	if vm.Name != spec.Name {
+		return false
+	}
+
+	mismatch := func(reported, requested int64) bool {
+		return requested != 0 && reported != requested
+	}
+	if mismatch(vm.CPU, spec.CPU) || mismatch(vm.MemoryMB, spec.MemoryMB) || mismatch(vm.DiskGB, spec.DiskGB) {
+		return false
+	}
+	if spec.Image != "" && vm.Image != spec.Image {
+		return false
+	}
+	if vm.Description != spec.Description {
+		return false
+	}
+	if !maps.Equal(vm.Labels, spec.Labels) {
+		return false
+	}
+	if vm.UserData != spec.UserData {
+		return false
+	}
+	if !sameStringSet(vm.SSHKeyNames, spec.SSHKeyNames) {
+		return false
+	}
+
+	return true
+}
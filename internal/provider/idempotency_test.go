@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClient_CreateVM_IdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+		attempt := len(keys)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if attempt == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: "test-vm"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(testRetryPolicy))
+
+	for _, spec := range []VM{{Name: "test-vm"}, {Name: "test-vm"}, {Name: "test-vm", CPU: 2}} {
+		if _, err := client.CreateVM(context.Background(), spec); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if len(keys) != 4 {
+		t.Fatalf("Expected 4 requests, got %d", len(keys))
+	}
+	if keys[0] == "" {
+		t.Fatal("Expected Idempotency-Key header to be set")
+	}
+	if keys[0] != keys[1] {
+		t.Errorf("Expected retry to reuse key %s, got %s", keys[0], keys[1])
+	}
+	if keys[2] != keys[0] {
+		t.Errorf("Expected a rerun of the same create to reuse key %s, got %s", keys[0], keys[2])
+	}
+	if keys[3] == keys[0] {
+		t.Error("Expected a new key for a create of another specification")
+	}
+}
+
+func TestClient_CreateVM_Conflict(t *testing.T) {
+	spec := VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20, Image: "ubuntu-22.04"}
+
+	tests := []struct {
+		name string
+		// retried makes the first create attempt fail with a retryable error, as when an
+		// earlier attempt created the VM but its response was lost
+		retried bool
+		// existing is the VM reported by the API; storeKey makes it report the key of the create
+		existing         *VM
+		storeKey         bool
+		expected         *VM
+		expectedErrorMsg string
+	}{
+		{
+			name:     "matching VM is adopted after a retry",
+			retried:  true,
+			existing: &VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20, Image: "ubuntu-22.04", Status: "running"},
+			expected: &VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20, Image: "ubuntu-22.04", Status: "running"},
+		},
+		{
+			name:     "VM with the key of the create is adopted with requested fields",
+			retried:  true,
+			existing: &VM{Name: "test-vm"},
+			storeKey: true,
+			expected: &spec,
+		},
+		{
+			name:     "VM with the key of an earlier run is adopted without a retry",
+			existing: &VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20, Image: "ubuntu-22.04"},
+			storeKey: true,
+			expected: &VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20, Image: "ubuntu-22.04"},
+		},
+		{
+			name:             "VM with another key is reported",
+			retried:          true,
+			existing:         &VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20, IdempotencyKey: "other"},
+			expectedErrorMsg: "created by another operation",
+		},
+		{
+			name:             "minimal API VM is reported",
+			retried:          true,
+			existing:         &VM{Name: "test-vm"},
+			expectedErrorMsg: "does not report enough of its configuration",
+		},
+		{
+			name:             "VM not reporting a requested field is reported",
+			retried:          true,
+			existing:         &VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20},
+			expectedErrorMsg: "already exists with a different configuration",
+		},
+		{
+			name:             "different VM is reported",
+			retried:          true,
+			existing:         &VM{Name: "test-vm", CPU: 8, MemoryMB: 2048, DiskGB: 20, Image: "ubuntu-22.04"},
+			expectedErrorMsg: "already exists with a different configuration",
+		},
+		{
+			name:             "matching VM on the first attempt is reported",
+			existing:         &VM{Name: "test-vm", CPU: 2, MemoryMB: 2048, DiskGB: 20, Image: "ubuntu-22.04"},
+			expectedErrorMsg: "409",
+		},
+		{
+			name:             "conflict without existing VM is reported",
+			retried:          true,
+			expectedErrorMsg: "409",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var creates int
+			var key string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodPost:
+					creates++
+					key = r.Header.Get(idempotencyKeyHeader)
+					if tt.retried && creates == 1 {
+						w.WriteHeader(http.StatusBadGateway)
+						return
+					}
+					w.WriteHeader(http.StatusConflict)
+					_ = json.NewEncoder(w).Encode(map[string]string{"error": "VM already exists"})
+				case r.URL.Path == vmPath+"/test-vm" && tt.existing != nil:
+					existing := *tt.existing
+					if tt.storeKey {
+						existing.IdempotencyKey = key
+					}
+					_ = json.NewEncoder(w).Encode(existing)
+				case r.URL.Path == vmPath+"/test-vm":
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					_ = json.NewEncoder(w).Encode([]*VM{})
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(testRetryPolicy))
+
+			vm, err := client.CreateVM(context.Background(), spec)
+			if tt.expectedErrorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErrorMsg) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedErrorMsg, err)
+				}
+				if !IsConflict(err) {
+					t.Errorf("Expected conflict error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			expected := *tt.expected
+			if tt.storeKey {
+				expected.IdempotencyKey = key
+			}
+			if !reflect.DeepEqual(vm, &expected) {
+				t.Errorf("Expected %+v, got %+v", &expected, vm)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// labelKeyPattern matches valid label keys. Keys cannot contain "=" because label filters are
+// sent to the API as key=value query parameters.
+var labelKeyPattern = regexp.MustCompile(`^[^=]+$`)
+
+// labelKeyValidator validates the keys of a labels map
+func labelKeyValidator() validator.String {
+	return stringvalidator.RegexMatches(labelKeyPattern, "must not be empty or contain '='")
+}
+
+// DefaultLabelsModel describes the default_labels block of the provider configuration
+type DefaultLabelsModel struct {
+	Labels types.Map `tfsdk:"labels"`
+}
+
+// WithDefaultLabels sets labels that are applied to every VM managed by the provider. Labels
+// configured on a resource take precedence.
+func WithDefaultLabels(labels map[string]string) ClientOption {
+	return func(c *Client) {
+		c.defaultLabels = maps.Clone(labels)
+	}
+}
+
+// defaultLabelsFromConfig reads the default_labels block of the provider configuration. It
+// returns nil when no default labels are configured.
+func defaultLabelsFromConfig(ctx context.Context, config DspcProviderModel) (map[string]string, error) {
+	if config.DefaultLabels == nil || config.DefaultLabels.Labels.IsNull() || config.DefaultLabels.Labels.IsUnknown() {
+		return nil, nil
+	}
+
+	var labels map[string]string
+	if diags := config.DefaultLabels.Labels.ElementsAs(ctx, &labels, false); diags.HasError() {
+		return nil, fmt.Errorf("invalid default_labels")
+	}
+	for key := range labels {
+		if !labelKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid default_labels key %q: keys must not be empty or contain '='", key)
+		}
+	}
+
+	return labels, nil
+}
+
+// effectiveLabels merges the default labels with the labels configured on a resource, which
+// take precedence. The result is unknown while any configured label is unknown, and null when
+// there are no labels at all.
+func effectiveLabels(defaults map[string]string, labels types.Map) types.Map {
+	if labels.IsUnknown() {
+		return types.MapUnknown(types.StringType)
+	}
+
+	merged := maps.Clone(defaults)
+	if merged == nil {
+		merged = make(map[string]string, len(labels.Elements()))
+	}
+	for key, element := range labels.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsUnknown() {
+			return types.MapUnknown(types.StringType)
+		}
+		if !value.IsNull() {
+			merged[key] = value.ValueString()
+		}
+	}
+
+	return labelsValue(merged)
+}
+
+// resourceLabels returns the labels to record in the labels attribute of a resource whose VM
+// reports the given labels. Labels inherited unchanged from the defaults are left out unless
+// they were already part of the configured labels, so importing a VM does not copy the
+// provider's default labels into its configuration.
+func resourceLabels(reported, defaults map[string]string, configured types.Map) types.Map {
+	own := make(map[string]string, len(reported))
+	for key, value := range reported {
+		_, wasConfigured := configured.Elements()[key]
+		if defaultValue, isDefault := defaults[key]; isDefault && defaultValue == value && !wasConfigured {
+			continue
+		}
+		own[key] = value
+	}
+
+	if len(own) == 0 && !configured.IsNull() {
+		return types.MapValueMust(types.StringType, map[string]attr.Value{})
+	}
+	return labelsValue(own)
+}
+
+// labelsValue converts labels into a Terraform map, which is null when there are no labels
+func labelsValue(labels map[string]string) types.Map {
+	if len(labels) == 0 {
+		return types.MapNull(types.StringType)
+	}
+
+	values := make(map[string]attr.Value, len(labels))
+	for key, value := range labels {
+		values[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, values)
+}
+
+// labelsMap converts a Terraform map of labels into a Go map. It returns nil for null,
+// unknown and empty maps.
+func labelsMap(value types.Map) map[string]string {
+	if value.IsNull() || value.IsUnknown() || len(value.Elements()) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(value.Elements()))
+	for key, element := range value.Elements() {
+		if s, ok := element.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			labels[key] = s.ValueString()
+		}
+	}
+	return labels
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEffectiveLabels(t *testing.T) {
+	defaults := map[string]string{"team": "platform", "env": "prod"}
+
+	tests := []struct {
+		name     string
+		defaults map[string]string
+		labels   types.Map
+		expected types.Map
+	}{
+		{
+			name:     "no labels",
+			labels:   types.MapNull(types.StringType),
+			expected: types.MapNull(types.StringType),
+		},
+		{
+			name:     "defaults only",
+			defaults: defaults,
+			labels:   types.MapNull(types.StringType),
+			expected: labelsValue(defaults),
+		},
+		{
+			name:     "resource labels override defaults",
+			defaults: defaults,
+			labels:   labelsValue(map[string]string{"env": "staging", "cost-center": "1234"}),
+			expected: labelsValue(map[string]string{"team": "platform", "env": "staging", "cost-center": "1234"}),
+		},
+		{
+			name:     "unknown labels",
+			defaults: defaults,
+			labels:   types.MapUnknown(types.StringType),
+			expected: types.MapUnknown(types.StringType),
+		},
+		{
+			name:     "unknown label value",
+			defaults: defaults,
+			labels: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"owner": types.StringUnknown(),
+			}),
+			expected: types.MapUnknown(types.StringType),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := effectiveLabels(tt.defaults, tt.labels)
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestResourceLabels(t *testing.T) {
+	defaults := map[string]string{"team": "platform"}
+
+	tests := []struct {
+		name       string
+		reported   map[string]string
+		configured types.Map
+		expected   types.Map
+	}{
+		{
+			name:       "imported VM leaves out defaults",
+			reported:   map[string]string{"team": "platform", "owner": "alice"},
+			configured: types.MapNull(types.StringType),
+			expected:   labelsValue(map[string]string{"owner": "alice"}),
+		},
+		{
+			name:       "overridden default is kept",
+			reported:   map[string]string{"team": "web"},
+			configured: types.MapNull(types.StringType),
+			expected:   labelsValue(map[string]string{"team": "web"}),
+		},
+		{
+			name:       "configured label equal to default is kept",
+			reported:   map[string]string{"team": "platform"},
+			configured: labelsValue(map[string]string{"team": "platform"}),
+			expected:   labelsValue(map[string]string{"team": "platform"}),
+		},
+		{
+			name:       "only defaults",
+			reported:   map[string]string{"team": "platform"},
+			configured: types.MapNull(types.StringType),
+			expected:   types.MapNull(types.StringType),
+		},
+		{
+			name:       "configured labels removed outside Terraform",
+			reported:   map[string]string{"team": "platform"},
+			configured: labelsValue(map[string]string{"owner": "alice"}),
+			expected:   types.MapValueMust(types.StringType, map[string]attr.Value{}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resourceLabels(tt.reported, defaults, tt.configured)
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDefaultLabelsFromConfig(t *testing.T) {
+	labels, err := defaultLabelsFromConfig(context.Background(), DspcProviderModel{})
+	if err != nil || labels != nil {
+		t.Errorf("Expected no default labels, got %v (%v)", labels, err)
+	}
+
+	labels, err = defaultLabelsFromConfig(context.Background(), DspcProviderModel{
+		DefaultLabels: &DefaultLabelsModel{Labels: labelsValue(map[string]string{"team": "platform"})},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(labels, map[string]string{"team": "platform"}) {
+		t.Errorf("Expected team=platform, got %v", labels)
+	}
+
+	_, err = defaultLabelsFromConfig(context.Background(), DspcProviderModel{
+		DefaultLabels: &DefaultLabelsModel{Labels: labelsValue(map[string]string{"team=web": "platform"})},
+	})
+	if err == nil {
+		t.Error("Expected error for key containing '=', got none")
+	}
+}
+
+func TestVirtualMachineResource_ModifyPlan_LabelsAll(t *testing.T) {
+	vmResource := &VMResource{
+		client: NewClient("http://localhost", "test-api-key", 30,
+			WithDefaultLabels(map[string]string{"team": "platform", "env": "prod"})),
+	}
+	// Capabilities are known not to be advertised, so no request is made
+	vmResource.client.capabilitiesLoaded = true
+
+	model := VMResourceModel{
+		Name:   types.StringValue("test-vm"),
+		Labels: labelsValue(map[string]string{"env": "staging"}),
+	}
+	plan := newVMResourcePlan(t, vmResource, model)
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+
+	vmResource.ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   plan,
+	}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got %v", resp.Diagnostics)
+	}
+
+	var result VMResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(context.Background(), &result)...)
+	expected := labelsValue(map[string]string{"team": "platform", "env": "staging"})
+	if !result.LabelsAll.Equal(expected) {
+		t.Errorf("Expected labels_all %s, got %s", expected, result.LabelsAll)
+	}
+}
+
+func TestVirtualMachineResource_CreateWithLabels(t *testing.T) {
+	var received VM
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(received)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: received.Name})
+	}))
+	defer server.Close()
+
+	vmResource := &VMResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+	plan := newVMResourcePlan(t, vmResource, VMResourceModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("test-vm"),
+		Image:     types.StringUnknown(),
+		Labels:    labelsValue(map[string]string{"env": "staging"}),
+		LabelsAll: labelsValue(map[string]string{"team": "platform", "env": "staging"}),
+		Status:    types.StringUnknown(),
+	})
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+
+	vmResource.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+	}
+
+	expected := map[string]string{"team": "platform", "env": "staging"}
+	if !reflect.DeepEqual(received.Labels, expected) {
+		t.Errorf("Expected labels %v in request, got %v", expected, received.Labels)
+	}
+}
+
+func TestVirtualMachineResource_ReadLabels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(VM{
+			Name:   "test-vm",
+			Labels: map[string]string{"team": "platform", "owner": "alice"},
+		})
+	}))
+	defer server.Close()
+
+	vmResource := &VMResource{
+		client: NewClient(server.URL, "test-api-key", 30, WithDefaultLabels(map[string]string{"team": "platform"})),
+	}
+
+	state := newVMResourceState(t, vmResource, VMResourceModel{Name: types.StringValue("test-vm")})
+	resp := &resource.ReadResponse{State: state}
+
+	vmResource.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+	}
+
+	var result VMResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &result)...)
+	if expected := labelsValue(map[string]string{"owner": "alice"}); !result.Labels.Equal(expected) {
+		t.Errorf("Expected labels %s, got %s", expected, result.Labels)
+	}
+	expected := labelsValue(map[string]string{"team": "platform", "owner": "alice"})
+	if !result.LabelsAll.Equal(expected) {
+		t.Errorf("Expected labels_all %s, got %s", expected, result.LabelsAll)
+	}
+}
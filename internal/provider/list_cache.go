@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultListCacheTTL is how long a fetched VM list is reused when no TTL is configured
+const defaultListCacheTTL = 5 * time.Second
+
+// vmListCache keeps the most recent VM list for a short time and coalesces concurrent
+// fetches, so that refreshing many resources at once results in a single list request.
+type vmListCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	vms       []*VM
+	valid     bool
+	fetchedAt time.Time
+	// generation is incremented on every invalidation so that fetches started before a
+	// change to the VM list do not repopulate the cache with stale data
+	generation uint64
+	inflight   *vmListCall
+}
+
+// vmListCall is a VM list fetch that other callers can wait on
+type vmListCall struct {
+	done chan struct{}
+	vms  []*VM
+	err  error
+	// canceled records that the fetch failed because its caller went away, in which case
+	// waiters with a live context start a fetch of their own
+	canceled bool
+}
+
+// newVMListCache creates a cache that reuses VM lists for ttl. A ttl of zero disables caching.
+func newVMListCache(ttl time.Duration) *vmListCache {
+	return &vmListCache{ttl: ttl}
+}
+
+// get returns the cached VM list, joins a fetch that is already in flight, or calls fetch
+func (c *vmListCache) get(ctx context.Context, fetch func(context.Context) ([]*VM, error)) ([]*VM, error) {
+	if c == nil || c.ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	for {
+		c.mu.Lock()
+
+		if c.valid && time.Since(c.fetchedAt) < c.ttl {
+			vms := c.vms
+			c.mu.Unlock()
+			return cloneVMs(vms), nil
+		}
+
+		if call := c.inflight; call != nil {
+			c.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-call.done:
+			}
+
+			if call.canceled {
+				continue
+			}
+			if call.err != nil {
+				return nil, call.err
+			}
+			return cloneVMs(call.vms), nil
+		}
+
+		call := &vmListCall{done: make(chan struct{})}
+		c.inflight = call
+		generation := c.generation
+		c.mu.Unlock()
+
+		vms, err := fetch(ctx)
+
+		c.mu.Lock()
+		call.vms, call.err = vms, err
+		call.canceled = err != nil && ctx.Err() != nil
+		if c.inflight == call {
+			c.inflight = nil
+		}
+		if err == nil && c.generation == generation {
+			c.vms = vms
+			c.valid = true
+			c.fetchedAt = time.Now()
+		}
+		c.mu.Unlock()
+		close(call.done)
+
+		if err != nil {
+			return nil, err
+		}
+		return cloneVMs(vms), nil
+	}
+}
+
+// invalidate drops the cached VM list and detaches any fetch in flight from future callers
+func (c *vmListCache) invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.valid = false
+	c.vms = nil
+	c.inflight = nil
+}
+
+// cloneVMs copies a VM list so callers cannot modify the cached entries
+func cloneVMs(vms []*VM) []*VM {
+	if vms == nil {
+		return nil
+	}
+
+	clones := make([]*VM, len(vms))
+	for i, vm := range vms {
+		if vm != nil {
+			clone := *vm
+			clone.Labels = maps.Clone(vm.Labels)
+			clone.SSHKeyNames = slices.Clone(vm.SSHKeyNames)
+			clones[i] = &clone
+		}
+	}
+	return clones
+}
@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_ListVMs_CoalescesConcurrentCalls(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		// Keep the request in flight long enough for all callers to join it
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode([]*VM{{Name: "vm1"}, {Name: "vm2"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vms, err := client.ListVMs(context.Background())
+			if err == nil && len(vms) != 2 {
+				err = errors.New("unexpected VM count")
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 list request, got %d", got)
+	}
+}
+
+func TestClient_ListVMs_Cache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		switch r.Method {
+		case http.MethodPost:
+			_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: "new-vm"})
+		case http.MethodDelete:
+			_ = json.NewEncoder(w).Encode(DeleteVMResponse{Deleted: "new-vm"})
+		default:
+			requests.Add(1)
+			_ = json.NewEncoder(w).Encode([]*VM{{Name: "vm1"}})
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		ttl              time.Duration
+		between          func(t *testing.T, client *Client)
+		expectedRequests int32
+	}{
+		{
+			name:             "second call served from cache",
+			ttl:              time.Minute,
+			expectedRequests: 1,
+		},
+		{
+			name:             "cache disabled",
+			ttl:              0,
+			expectedRequests: 2,
+		},
+		{
+			name: "cache expires",
+			ttl:  10 * time.Millisecond,
+			between: func(*testing.T, *Client) {
+				time.Sleep(20 * time.Millisecond)
+			},
+			expectedRequests: 2,
+		},
+		{
+			name: "invalidated by create",
+			ttl:  time.Minute,
+			between: func(t *testing.T, client *Client) {
+				if _, err := client.CreateVM(context.Background(), VM{Name: "new-vm"}); err != nil {
+					t.Fatalf("CreateVM failed: %v", err)
+				}
+			},
+			expectedRequests: 2,
+		},
+		{
+			name: "invalidated by delete",
+			ttl:  time.Minute,
+			between: func(t *testing.T, client *Client) {
+				if err := client.DeleteVM(context.Background(), "new-vm"); err != nil {
+					t.Fatalf("DeleteVM failed: %v", err)
+				}
+			},
+			expectedRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			client := NewClient(server.URL, "test-api-key", 30, WithListCacheTTL(tt.ttl))
+
+			if _, err := client.ListVMs(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.between != nil {
+				tt.between(t, client)
+			}
+			if _, err := client.ListVMs(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if got := requests.Load(); got != tt.expectedRequests {
+				t.Errorf("Expected %d list requests, got %d", tt.expectedRequests, got)
+			}
+		})
+	}
+}
+
+func TestClient_ListVMs_CacheReturnsCopies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode([]*VM{{
+			Name:        "vm1",
+			Labels:      map[string]string{"team": "web"},
+			SSHKeyNames: []string{"alice"},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	vms, err := client.ListVMs(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	vms[0].Name = "modified"
+	vms[0].Labels["team"] = "modified"
+	vms[0].SSHKeyNames[0] = "modified"
+
+	vms, err = client.ListVMs(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if vms[0].Name != "vm1" {
+		t.Errorf("Expected cached VM to be unaffected by caller changes, got %s", vms[0].Name)
+	}
+	if vms[0].Labels["team"] != "web" {
+		t.Errorf("Expected cached labels to be unaffected by caller changes, got %v", vms[0].Labels)
+	}
+	if vms[0].SSHKeyNames[0] != "alice" {
+		t.Errorf("Expected cached SSH key names to be unaffected by caller changes, got %v", vms[0].SSHKeyNames)
+	}
+}
+
+func TestClient_ListVMs_ErrorsAreNotCached(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode([]*VM{{Name: "vm1"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30)
+
+	if _, err := client.ListVMs(context.Background()); err == nil {
+		t.Fatal("Expected error on first call, got nil")
+	}
+	if _, err := client.ListVMs(context.Background()); err != nil {
+		t.Errorf("Expected no error on second call, got %v", err)
+	}
+}
+
+func TestVMListCache_WaiterSurvivesCanceledLeader(t *testing.T) {
+	cache := newVMListCache(time.Minute)
+
+	leaderStarted := make(chan struct{})
+	var fetches atomic.Int32
+	fetch := func(ctx context.Context) ([]*VM, error) {
+		if fetches.Add(1) == 1 {
+			close(leaderStarted)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return []*VM{{Name: "vm1"}}, nil
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cache.get(leaderCtx, fetch)
+		leaderErr <- err
+	}()
+	<-leaderStarted
+
+	waiterResult := make(chan []*VM, 1)
+	go func() {
+		vms, err := cache.get(context.Background(), fetch)
+		if err != nil {
+			t.Errorf("Expected waiter to succeed, got %v", err)
+		}
+		waiterResult <- vms
+	}()
+
+	// Give the waiter time to join the in-flight call before the leader goes away
+	time.Sleep(20 * time.Millisecond)
+	cancelLeader()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected leader to be canceled, got %v", err)
+	}
+	if vms := <-waiterResult; len(vms) != 1 {
+		t.Errorf("Expected waiter to receive 1 VM, got %d", len(vms))
+	}
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logSubsystem is the tflog subsystem for API traffic. Its level can be set separately
+// with TF_LOG_PROVIDER_DSPC_API.
+const logSubsystem = "dspc_api"
+
+// maxLoggedBodySize limits how much of a request or response body is logged
+const maxLoggedBodySize = 64 << 10
+
+// redactedValue replaces the values of sensitive fields in logged bodies
+const redactedValue = "***"
+
+// sensitiveFieldPattern matches JSON field names whose values are never logged
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)(password|secret|token|api_?key|private_?key|credential)`)
+
+// logContext prepares ctx for logging API traffic. The bearer token and API key are masked
+// wherever they appear, and the Authorization header is never logged.
+func (c *Client) logContext(ctx context.Context, token string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, logSubsystem, "authorization")
+
+	var secrets []string
+	for _, secret := range []string{token, c.apiKey} {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	if len(secrets) > 0 {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, logSubsystem, secrets...)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, logSubsystem, secrets...)
+	}
+
+	return ctx
+}
+
+// logRequest logs an outgoing API request. The body is only logged at TRACE level.
+func logRequest(ctx context.Context, req *http.Request, body []byte, attempt int) {
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"attempt": attempt + 1,
+	}
+	if requestID := req.Header.Get(requestIDHeader); requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystem, "Sending API request", fields)
+
+	if len(body) > 0 {
+		tflog.SubsystemTrace(ctx, logSubsystem, "API request body", map[string]interface{}{
+			"method": req.Method,
+			"url":    req.URL.String(),
+			"body":   redactBody(body),
+		})
+	}
+}
+
+// logResponse logs the outcome of an API request. At TRACE level the response body is logged
+// too; it is read ahead and put back so callers can still consume it.
+func logResponse(ctx context.Context, req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	fields := map[string]interface{}{
+		"method":      req.Method,
+		"url":         req.URL.String(),
+		"duration_ms": duration.Milliseconds(),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "API request failed", fields)
+		return
+	}
+
+	fields["status"] = resp.StatusCode
+	if requestID := resp.Header.Get(requestIDHeader); requestID != "" {
+		fields["request_id"] = requestID
+	} else if requestID := req.Header.Get(requestIDHeader); requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystem, "Received API response", fields)
+
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+
+	head, readErr := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+
+	if readErr == nil && len(head) > 0 {
+		tflog.SubsystemTrace(ctx, logSubsystem, "API response body", map[string]interface{}{
+			"method": req.Method,
+			"url":    req.URL.String(),
+			"status": resp.StatusCode,
+			"body":   redactBody(head),
+		})
+	}
+}
+
+// redactBody returns body for logging with the values of sensitive JSON fields replaced.
+// Bodies that are not valid JSON, for example because they were truncated, are returned as is.
+func redactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// redactValue replaces the values of sensitive fields in a decoded JSON value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFieldPattern.MatchString(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestClient_LogsRequestsAndResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-ID", "req-123")
+		_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: "test-vm"})
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := NewClient(server.URL, "super-secret-key", 30)
+	type createRequest struct {
+		VM
+		Password string `json:"password"`
+	}
+	if err := client.do(ctx, http.MethodPost, "/virtualmachine", createRequest{
+		VM:       VM{Name: "test-vm"},
+		Password: "hunter2",
+	}, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Contains(output.String(), "super-secret-key") {
+		t.Errorf("Expected API key to be masked, got logs:\n%s", output.String())
+	}
+	if strings.Contains(output.String(), "hunter2") {
+		t.Errorf("Expected password to be redacted, got logs:\n%s", output.String())
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("Failed to decode log entries: %v", err)
+	}
+
+	messages := map[string]map[string]interface{}{}
+	for _, entry := range entries {
+		if entry["@module"] != "provider."+logSubsystem {
+			t.Errorf("Expected module provider.%s, got %v", logSubsystem, entry["@module"])
+		}
+		messages[entry["@message"].(string)] = entry
+	}
+
+	response, ok := messages["Received API response"]
+	if !ok {
+		t.Fatalf("Expected response log entry, got %v", entries)
+	}
+	if response["method"] != http.MethodPost {
+		t.Errorf("Expected method POST, got %v", response["method"])
+	}
+	if response["url"] != server.URL+"/virtualmachine" {
+		t.Errorf("Expected url %s/virtualmachine, got %v", server.URL, response["url"])
+	}
+	if response["status"] != float64(http.StatusOK) {
+		t.Errorf("Expected status 200, got %v", response["status"])
+	}
+	if response["request_id"] != "req-123" {
+		t.Errorf("Expected request_id req-123, got %v", response["request_id"])
+	}
+	if _, ok := response["duration_ms"]; !ok {
+		t.Error("Expected duration_ms field")
+	}
+
+	requestBody, ok := messages["API request body"]
+	if !ok {
+		t.Fatalf("Expected request body log entry, got %v", entries)
+	}
+	if !strings.Contains(requestBody["body"].(string), `"password":"***"`) {
+		t.Errorf("Expected redacted password in request body, got %v", requestBody["body"])
+	}
+
+	responseBody, ok := messages["API response body"]
+	if !ok {
+		t.Fatalf("Expected response body log entry, got %v", entries)
+	}
+	if !strings.Contains(responseBody["body"].(string), "test-vm") {
+		t.Errorf("Expected response body to be logged, got %v", responseBody["body"])
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "sensitive fields",
+			body:     `{"vmName":"vm1","api_key":"k","nested":{"clientSecret":"s"},"items":[{"token":"t"}]}`,
+			expected: `{"api_key":"***","items":[{"token":"***"}],"nested":{"clientSecret":"***"},"vmName":"vm1"}`,
+		},
+		{
+			name:     "no sensitive fields",
+			body:     `[{"vmName":"vm1"}]`,
+			expected: `[{"vmName":"vm1"}]`,
+		},
+		{
+			name:     "not JSON",
+			body:     "internal error",
+			expected: "internal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := redactBody([]byte(tt.body)); result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
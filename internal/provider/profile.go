@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultProfileName is the profile used when neither the profile attribute nor DSPC_PROFILE is set
+const defaultProfileName = "default"
+
+// profileKeys lists the settings a profile may contain. Each key corresponds to the
+// DSPC_<KEY> environment variable and the provider attribute of the same name.
+var profileKeys = map[string]bool{
+	"endpoint":                    true,
+	"api_key":                     true,
+	"timeout":                     true,
+	"max_retries":                 true,
+	"retry_wait_min":              true,
+	"retry_wait_max":              true,
+	"list_cache_ttl":              true,
+	"max_requests_per_second":     true,
+	"max_concurrent_requests":     true,
+	"ca_cert_file":                true,
+	"ca_cert_pem":                 true,
+	"client_cert":                 true,
+	"client_key":                  true,
+	"tls_min_version":             true,
+	"insecure_skip_verify":        true,
+	"proxy_url":                   true,
+	"no_proxy":                    true,
+	"proxy_username":              true,
+	"proxy_password":              true,
+	"credential_process":          true,
+	"skip_credentials_validation": true,
+	"oauth2_token_url":            true,
+	"oauth2_client_id":            true,
+	"oauth2_client_secret":        true,
+	"oauth2_scopes":               true,
+}
+
+// configSources resolves provider settings that are not set as attributes, first from
+// environment variables and then from the selected profile of the config file
+type configSources struct {
+	profileName string
+	profile     map[string]string
+}
+
+// defaultConfigFile returns the path of the config file, ~/.dspc/config unless DSPC_CONFIG_FILE is set
+func defaultConfigFile() (string, error) {
+	if path := os.Getenv("DSPC_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory for the DSPC config file: %w", err)
+	}
+	return filepath.Join(home, ".dspc", "config"), nil
+}
+
+// loadConfigSources selects the profile named by the profile attribute or DSPC_PROFILE and
+// loads its settings. Without an explicit profile, the default profile is used if it exists.
+func loadConfigSources(config DspcProviderModel) (*configSources, error) {
+	name := os.Getenv("DSPC_PROFILE")
+	if !config.Profile.IsNull() && !config.Profile.IsUnknown() && config.Profile.ValueString() != "" {
+		name = config.Profile.ValueString()
+	}
+	explicit := name != ""
+	if !explicit {
+		name = defaultProfileName
+	}
+
+	path, err := defaultConfigFile()
+	if err != nil {
+		if explicit {
+			return nil, err
+		}
+		return &configSources{}, nil
+	}
+
+	profiles, err := readProfiles(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return &configSources{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		if explicit {
+			return nil, fmt.Errorf("profile %q not found in %s", name, path)
+		}
+		return &configSources{}, nil
+	}
+
+	return &configSources{profileName: name, profile: profile}, nil
+}
+
+// readProfiles parses an INI config file into its named profiles:
+//
+//	[default]
+//	endpoint = https://vm-deployer.example.com:8080
+//	api_key  = ...
+//
+// Lines starting with # or ; are comments. Unknown keys are rejected to catch typos.
+func readProfiles(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DSPC config file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("%s:%d: empty profile name", path, lineNumber)
+			}
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = map[string]string{}
+			}
+			current = profiles[name]
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: expected 'key = value'", path, lineNumber)
+			}
+			if current == nil {
+				return nil, fmt.Errorf("%s:%d: setting outside of a [profile] section", path, lineNumber)
+			}
+
+			key = strings.TrimSpace(key)
+			if !profileKeys[key] {
+				return nil, fmt.Errorf("%s:%d: unknown setting %q, expected one of: %s",
+					path, lineNumber, key, strings.Join(sortedProfileKeys(), ", "))
+			}
+			current[key] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read DSPC config file: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// sortedProfileKeys returns the supported profile settings in alphabetical order
+func sortedProfileKeys() []string {
+	keys := make([]string, 0, len(profileKeys))
+	for key := range profileKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookup returns the value of envVar, falling back to the matching profile setting. The
+// second result describes where the value came from for error messages.
+func (s *configSources) lookup(envVar string) (string, string) {
+	if value := os.Getenv(envVar); value != "" {
+		return value, envVar + " environment variable"
+	}
+
+	if s == nil || s.profile == nil {
+		return "", ""
+	}
+
+	key := strings.ToLower(strings.TrimPrefix(envVar, "DSPC_"))
+	if value := s.profile[key]; value != "" {
+		return value, fmt.Sprintf("%s in profile %q", key, s.profileName)
+	}
+	return "", ""
+}
+
+// stringSetting resolves a string setting from a provider attribute, falling back to an
+// environment variable and then the profile
+func (s *configSources) stringSetting(value types.String, envVar string) string {
+	if !value.IsNull() && !value.IsUnknown() && value.ValueString() != "" {
+		return value.ValueString()
+	}
+	setting, _ := s.lookup(envVar)
+	return setting
+}
+
+// int64Setting resolves an integer setting from a provider attribute, falling back to an
+// environment variable and then the profile. The boolean result reports whether any source
+// provided a value.
+func (s *configSources) int64Setting(value types.Int64, envVar string) (int64, bool, error) {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueInt64(), true, nil
+	}
+
+	if setting, source := s.lookup(envVar); setting != "" {
+		parsed, err := strconv.ParseInt(setting, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid value %q for %s: %w", setting, source, err)
+		}
+		return parsed, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// boolSetting resolves a boolean setting from a provider attribute, falling back to an
+// environment variable and then the profile. The second result reports whether any source
+// provided a value.
+func (s *configSources) boolSetting(value types.Bool, envVar string) (bool, bool, error) {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueBool(), true, nil
+	}
+
+	if setting, source := s.lookup(envVar); setting != "" {
+		parsed, err := strconv.ParseBool(setting)
+		if err != nil {
+			return false, false, fmt.Errorf("invalid value %q for %s: %w", setting, source, err)
+		}
+		return parsed, true, nil
+	}
+
+	return false, false, nil
+}
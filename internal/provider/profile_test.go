@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testProfiles = `# DSPC profiles
+[default]
+endpoint = https://default.example.com
+api_key  = default-key
+
+[staging]
+endpoint    = https://staging.example.com
+api_key     = staging-key
+timeout     = 90
+max_retries = 7
+; comments are allowed
+`
+
+// writeConfigFile writes content to a temporary DSPC config file and points DSPC_CONFIG_FILE at it
+func writeConfigFile(t *testing.T, content string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("DSPC_CONFIG_FILE", path)
+}
+
+func TestNewClientFromConfig_Profiles(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             DspcProviderModel
+		env                map[string]string
+		expectedEndpoint   string
+		expectedAPIKey     string
+		expectedTimeout    time.Duration
+		expectedMaxRetries int
+	}{
+		{
+			name:               "default profile used implicitly",
+			expectedEndpoint:   "https://default.example.com",
+			expectedAPIKey:     "default-key",
+			expectedTimeout:    30 * time.Second,
+			expectedMaxRetries: 3,
+		},
+		{
+			name:               "profile selected by attribute",
+			config:             DspcProviderModel{Profile: types.StringValue("staging")},
+			expectedEndpoint:   "https://staging.example.com",
+			expectedAPIKey:     "staging-key",
+			expectedTimeout:    90 * time.Second,
+			expectedMaxRetries: 7,
+		},
+		{
+			name:               "profile selected by environment",
+			env:                map[string]string{"DSPC_PROFILE": "staging"},
+			expectedEndpoint:   "https://staging.example.com",
+			expectedAPIKey:     "staging-key",
+			expectedTimeout:    90 * time.Second,
+			expectedMaxRetries: 7,
+		},
+		{
+			name: "attribute and environment take precedence over profile",
+			config: DspcProviderModel{
+				Profile:  types.StringValue("staging"),
+				Endpoint: types.StringValue("https://attribute.example.com"),
+			},
+			env:                map[string]string{"DSPC_API_KEY": "env-key", "DSPC_ENDPOINT": "https://env.example.com"},
+			expectedEndpoint:   "https://attribute.example.com",
+			expectedAPIKey:     "env-key",
+			expectedTimeout:    90 * time.Second,
+			expectedMaxRetries: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigFile(t, testProfiles)
+			envVars := []string{"DSPC_PROFILE", "DSPC_ENDPOINT", "DSPC_API_KEY", "DSPC_TIMEOUT", "DSPC_MAX_RETRIES"}
+			for _, envVar := range envVars {
+				t.Setenv(envVar, tt.env[envVar])
+			}
+
+			client, err := NewClientFromConfig(tt.config)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if client.endpoint != tt.expectedEndpoint {
+				t.Errorf("Expected endpoint %s, got %s", tt.expectedEndpoint, client.endpoint)
+			}
+			if client.apiKey != tt.expectedAPIKey {
+				t.Errorf("Expected API key %s, got %s", tt.expectedAPIKey, client.apiKey)
+			}
+			if client.httpClient.Timeout != tt.expectedTimeout {
+				t.Errorf("Expected timeout %s, got %s", tt.expectedTimeout, client.httpClient.Timeout)
+			}
+			if client.retry.MaxRetries != tt.expectedMaxRetries {
+				t.Errorf("Expected %d max retries, got %d", tt.expectedMaxRetries, client.retry.MaxRetries)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig_ProfileErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		profile          string
+		expectedErrorMsg string
+	}{
+		{
+			name:             "unknown profile",
+			content:          testProfiles,
+			profile:          "production",
+			expectedErrorMsg: `profile "production" not found`,
+		},
+		{
+			name:             "unknown setting",
+			content:          "[default]\nendpoint = https://default.example.com\napikey = typo\n",
+			expectedErrorMsg: `:3: unknown setting "apikey"`,
+		},
+		{
+			name:             "setting outside profile",
+			content:          "endpoint = https://default.example.com\n",
+			expectedErrorMsg: "outside of a [profile] section",
+		},
+		{
+			name:             "invalid number",
+			content:          "[default]\nendpoint = https://default.example.com\napi_key = key\nmax_retries = many\n",
+			expectedErrorMsg: `invalid value "many" for max_retries in profile "default"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigFile(t, tt.content)
+			for _, envVar := range []string{"DSPC_PROFILE", "DSPC_ENDPOINT", "DSPC_API_KEY", "DSPC_MAX_RETRIES"} {
+				t.Setenv(envVar, "")
+			}
+
+			_, err := NewClientFromConfig(DspcProviderModel{Profile: types.StringValue(tt.profile)})
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErrorMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedErrorMsg, err)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig_MissingConfigFile(t *testing.T) {
+	t.Setenv("DSPC_CONFIG_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("DSPC_PROFILE", "")
+
+	// Without an explicit profile a missing file is not an error
+	_, err := NewClientFromConfig(DspcProviderModel{
+		Endpoint: types.StringValue("http://localhost:8080"),
+		APIKey:   types.StringValue("test-key"),
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	_, err = NewClientFromConfig(DspcProviderModel{
+		Endpoint: types.StringValue("http://localhost:8080"),
+		APIKey:   types.StringValue("test-key"),
+		Profile:  types.StringValue("staging"),
+	})
+	if err == nil {
+		t.Error("Expected error for explicit profile without config file, got nil")
+	}
+}
@@ -6,11 +6,16 @@ package provider
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -24,9 +29,36 @@ type DspcProvider struct {
 
 // DspcProviderModel describes the provider data model.
 type DspcProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
-	Timeout  types.Int64  `tfsdk:"timeout"`
-	APIKey   types.String `tfsdk:"api_key"`
+	Endpoint     types.String `tfsdk:"endpoint"`
+	Timeout      types.Int64  `tfsdk:"timeout"`
+	APIKey       types.String `tfsdk:"api_key"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin types.Int64  `tfsdk:"retry_wait_min"`
+	RetryWaitMax types.Int64  `tfsdk:"retry_wait_max"`
+	ListCacheTTL types.Int64  `tfsdk:"list_cache_ttl"`
+
+	MaxRequestsPerSecond  types.Int64 `tfsdk:"max_requests_per_second"`
+	MaxConcurrentRequests types.Int64 `tfsdk:"max_concurrent_requests"`
+
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	TLSMinVersion      types.String `tfsdk:"tls_min_version"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+
+	ProxyURL      types.String `tfsdk:"proxy_url"`
+	NoProxy       types.String `tfsdk:"no_proxy"`
+	ProxyUsername types.String `tfsdk:"proxy_username"`
+	ProxyPassword types.String `tfsdk:"proxy_password"`
+
+	SkipCredentialsValidation types.Bool `tfsdk:"skip_credentials_validation"`
+
+	Profile           types.String `tfsdk:"profile"`
+	CredentialProcess types.String `tfsdk:"credential_process"`
+	OAuth2            *OAuth2Model `tfsdk:"oauth2"`
+
+	DefaultLabels *DefaultLabelsModel `tfsdk:"default_labels"`
 }
 
 // Metadata updates the provided metadata with the provider type name and version.
@@ -42,8 +74,9 @@ func (p *DspcProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 			"resources across different platforms.",
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				Description: "The endpoint URL for the DSPC VM Deployer API. Required - can be set " +
-					"via provider config or DSPC_ENDPOINT environment variable.",
+				Description: "The endpoint URL for the DSPC VM Deployer API, including any path prefix such as " +
+					"https://gw.example.com/dspc/v1. Required - can be set via provider config or DSPC_ENDPOINT " +
+					"environment variable.",
 				Optional: true,
 			},
 			"timeout": schema.Int64Attribute{
@@ -56,6 +89,174 @@ func (p *DspcProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Optional:  true,
 				Sensitive: true,
 			},
+			"max_retries": schema.Int64Attribute{
+				Description: "Maximum number of retries for transient API failures (HTTP 429, 502, 503, 504 " +
+					"and connection errors). Set to 0 to disable retries. Defaults to 3. Can also be set via " +
+					"the DSPC_MAX_RETRIES environment variable. Creates are retried with the same Idempotency-Key " +
+					"header.",
+				Optional: true,
+			},
+			"retry_wait_min": schema.Int64Attribute{
+				Description: "Minimum time in seconds to wait before retrying a failed request. Defaults to 1. " +
+					"Can also be set via the DSPC_RETRY_WAIT_MIN environment variable.",
+				Optional: true,
+			},
+			"retry_wait_max": schema.Int64Attribute{
+				Description: "Maximum time in seconds to wait between retries, including waits requested by " +
+					"the server via Retry-After. Defaults to 30. Can also be set via the DSPC_RETRY_WAIT_MAX " +
+					"environment variable.",
+				Optional: true,
+			},
+			"list_cache_ttl": schema.Int64Attribute{
+				Description: "Time in seconds a fetched VM list is shared between resources and data sources " +
+					"before it is requested again. Concurrent list requests are always combined while caching " +
+					"is enabled. Set to 0 to disable caching. Defaults to 5. Can also be set via the " +
+					"DSPC_LIST_CACHE_TTL environment variable.",
+				Optional: true,
+			},
+			"max_requests_per_second": schema.Int64Attribute{
+				Description: "Maximum average number of API requests per second, including retries. Short bursts " +
+					"of up to the same number of requests are allowed. Set to 0 for no limit. Defaults to 0. Can " +
+					"also be set via the DSPC_MAX_REQUESTS_PER_SECOND environment variable.",
+				Optional: true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Maximum number of API requests in flight at the same time, across all resources " +
+					"and data sources. Set to 0 for no limit. Defaults to 0. Can also be set via the " +
+					"DSPC_MAX_CONCURRENT_REQUESTS environment variable.",
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM file with additional CA certificates to trust when verifying the API " +
+					"server, for endpoints signed by an internal CA. Can also be set via the DSPC_CA_CERT_FILE " +
+					"environment variable.",
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM-encoded CA certificates to trust in addition to the system roots. Can also be " +
+					"set via the DSPC_CA_CERT_PEM environment variable.",
+				Optional: true,
+			},
+			"client_cert": schema.StringAttribute{
+				Description: "Client certificate for mutual TLS, as PEM content or a path to a PEM file. Requires " +
+					"client_key. Can also be set via the DSPC_CLIENT_CERT environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				Description: "Private key for the client certificate, as PEM content or a path to a PEM file. " +
+					"Requires client_cert. Can also be set via the DSPC_CLIENT_KEY environment variable.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"tls_min_version": schema.StringAttribute{
+				Description: "Minimum TLS version to accept, either `1.2` or `1.3`. Defaults to `1.2`. Can also " +
+					"be set via the DSPC_TLS_MIN_VERSION environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("1.2", "1.3"),
+				},
+			},
+			"profile": schema.StringAttribute{
+				Description: "Name of the profile in the DSPC config file (~/.dspc/config, or the path in " +
+					"DSPC_CONFIG_FILE) to read settings from. Settings given as attributes or environment " +
+					"variables take precedence over the profile. Defaults to the `default` profile if it " +
+					"exists. Can also be set via the DSPC_PROFILE environment variable.",
+				Optional: true,
+			},
+			"credential_process": schema.StringAttribute{
+				Description: "A command that prints a JSON object with a `token` and an optional RFC 3339 " +
+					"`expires_at` to stdout. The token is used instead of api_key, and the command is run " +
+					"again when the token expires or is rejected by the API. Can also be set via the " +
+					"DSPC_CREDENTIAL_PROCESS environment variable.",
+				Optional: true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Disable verification of the API server certificate. Only intended for lab " +
+					"environments; prefer ca_cert_file or ca_cert_pem. Can also be set via the " +
+					"DSPC_INSECURE_SKIP_VERIFY environment variable.",
+				Optional: true,
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				Description: "Skip the request made while configuring the provider to check that the API is " +
+					"reachable and accepts the credentials. Defaults to false. Can also be set via the " +
+					"DSPC_SKIP_CREDENTIALS_VALIDATION environment variable.",
+				Optional: true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "URL of the HTTP proxy used to reach the API, such as http://proxy.example.com:3128. " +
+					"Can also be set via the DSPC_PROXY_URL environment variable. Defaults to the standard " +
+					"HTTPS_PROXY and HTTP_PROXY environment variables.",
+				Optional: true,
+			},
+			"no_proxy": schema.StringAttribute{
+				Description: "Comma-separated hosts, domains and CIDR ranges that are reached without the proxy. " +
+					"Can also be set via the DSPC_NO_PROXY environment variable. Defaults to the standard " +
+					"NO_PROXY environment variable.",
+				Optional: true,
+			},
+			"proxy_username": schema.StringAttribute{
+				Description: "Username for basic authentication with the proxy. Can also be set via the " +
+					"DSPC_PROXY_USERNAME environment variable.",
+				Optional: true,
+			},
+			"proxy_password": schema.StringAttribute{
+				Description: "Password for basic authentication with the proxy. Requires proxy_username. Can " +
+					"also be set via the DSPC_PROXY_PASSWORD environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"default_labels": schema.SingleNestedBlock{
+				Description: "Labels applied to every virtual machine managed by the provider. Labels set on a " +
+					"resource take precedence over these defaults, and the merged set is shown in its labels_all " +
+					"attribute.",
+				Attributes: map[string]schema.Attribute{
+					"labels": schema.MapAttribute{
+						Description: "The default labels as key/value pairs.",
+						ElementType: types.StringType,
+						Optional:    true,
+						Validators: []validator.Map{
+							mapvalidator.KeysAre(labelKeyValidator()),
+						},
+					},
+				},
+			},
+			"oauth2": schema.SingleNestedBlock{
+				Description: "Authenticate with access tokens obtained through the OAuth2 client credentials " +
+					"grant instead of a static API key. Tokens are cached and refreshed before they expire. " +
+					"Each attribute can also be set via a DSPC_OAUTH2_* environment variable.",
+				Attributes: map[string]schema.Attribute{
+					"token_url": schema.StringAttribute{
+						Description: "The token endpoint of the OAuth2 issuer. Can also be set via the " +
+							"DSPC_OAUTH2_TOKEN_URL environment variable.",
+						Optional: true,
+					},
+					"client_id": schema.StringAttribute{
+						Description: "The OAuth2 client ID. Can also be set via the DSPC_OAUTH2_CLIENT_ID " +
+							"environment variable.",
+						Optional: true,
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "The OAuth2 client secret. Can also be set via the DSPC_OAUTH2_CLIENT_SECRET " +
+							"environment variable.",
+						Optional:  true,
+						Sensitive: true,
+					},
+					"scopes": schema.ListAttribute{
+						Description: "Scopes to request. Can also be set via the DSPC_OAUTH2_SCOPES environment " +
+							"variable as a comma-separated list.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -70,13 +271,52 @@ func (p *DspcProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		return
 	}
 
+	if !config.Endpoint.IsNull() && !config.Endpoint.IsUnknown() && config.Endpoint.ValueString() != "" {
+		if err := validateEndpoint(config.Endpoint.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid Endpoint",
+				fmt.Sprintf("The endpoint %q is not a valid DSPC API URL: %s. Expected a URL such as "+
+					"https://vm-deployer.example.com:8080 or https://gw.example.com/dspc/v1.",
+					config.Endpoint.ValueString(), err),
+			)
+			return
+		}
+	}
+
 	// Create the API client (handles all config extraction and defaults)
-	client, err := NewClientFromConfig(config)
+	client, err := NewClientFromConfig(config, WithUserAgent(userAgent(p.version, req.TerraformVersion)))
 	if err != nil {
 		resp.Diagnostics.AddError("Provider Configuration Error", err.Error())
 		return
 	}
 
+	if client.insecureSkipVerify() {
+		resp.Diagnostics.AddWarning(
+			"TLS Certificate Verification Disabled",
+			"insecure_skip_verify is enabled, so the identity of the DSPC API server is not verified. "+
+				"Only use this in lab environments; configure ca_cert_file or ca_cert_pem instead.",
+		)
+	}
+
+	skipValidation, err := skipCredentialsValidationFromConfig(config)
+	if err != nil {
+		resp.Diagnostics.AddError("Provider Configuration Error", err.Error())
+		return
+	}
+	if !skipValidation {
+		// A wrong endpoint or revoked key is reported now rather than halfway through an apply
+		if err := client.CheckConnection(ctx); err != nil {
+			addConnectionError(&resp.Diagnostics, client.endpoint, err)
+			return
+		}
+
+		// Discovered once here so resources can check their attributes against it during planning
+		if _, err := client.Capabilities(ctx); err != nil {
+			resp.Diagnostics.AddWarning("Unable to Determine DSPC API Capabilities", err.Error())
+		}
+	}
+
 	// Store the client in the response data for resources and data sources to use
 	resp.ResourceData = client
 	resp.DataSourceData = client
@@ -86,6 +326,7 @@ func (p *DspcProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 func (p *DspcProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewVMResource,
+		NewSSHKeyResource,
 	}
 }
 
@@ -93,6 +334,7 @@ func (p *DspcProvider) Resources(_ context.Context) []func() resource.Resource {
 func (p *DspcProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewVMDataSource,
+		NewVMLookupDataSource,
 	}
 }
 
@@ -139,6 +139,11 @@ func TestProviderSchema(t *testing.T) {
 	if _, ok := attributes["api_key"]; !ok {
 		t.Error("Provider schema missing 'api_key' attribute")
 	}
+	for _, name := range []string{"max_retries", "retry_wait_min", "retry_wait_max"} {
+		if _, ok := attributes[name]; !ok {
+			t.Errorf("Provider schema missing '%s' attribute", name)
+		}
+	}
 }
 
 func TestProviderMetadata(t *testing.T) {
@@ -163,8 +168,8 @@ func TestProviderResources(t *testing.T) {
 
 	resources := p.Resources(context.Background())
 
-	if len(resources) != 1 {
-		t.Errorf("Expected 1 resource, got %d", len(resources))
+	if len(resources) != 2 {
+		t.Errorf("Expected 2 resources, got %d", len(resources))
 	}
 
 	// Test that the resource factory returns a valid resource
@@ -179,8 +184,8 @@ func TestProviderDataSources(t *testing.T) {
 
 	dataSources := p.DataSources(context.Background())
 
-	if len(dataSources) != 1 {
-		t.Errorf("Expected 1 data source, got %d", len(dataSources))
+	if len(dataSources) != 2 {
+		t.Errorf("Expected 2 data sources, got %d", len(dataSources))
 	}
 
 	// Test that the data source factory returns a valid data source
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxySettings describes the HTTP proxy used to reach the API. Settings left empty fall back
+// to the standard HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+type ProxySettings struct {
+	// URL is the proxy for both HTTP and HTTPS endpoints, for example http://proxy.example.com:3128
+	URL string
+	// NoProxy is a comma-separated list of hosts, domains and CIDR ranges reached directly
+	NoProxy string
+	// Username and Password authenticate with the proxy using basic authentication
+	Username string
+	Password string
+}
+
+// IsDefault reports whether the settings leave Go's default proxy behavior unchanged
+func (s ProxySettings) IsDefault() bool {
+	return s == ProxySettings{}
+}
+
+// ProxyFunc returns the function that selects the proxy for each request, suitable for
+// http.Transport.Proxy
+func (s ProxySettings) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	config := httpproxy.FromEnvironment()
+
+	if s.URL != "" {
+		proxyURL, err := url.Parse(s.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		if proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy_url %q: expected a URL such as http://proxy.example.com:3128", s.URL)
+		}
+
+		config.HTTPProxy = s.URL
+		config.HTTPSProxy = s.URL
+	}
+	if s.NoProxy != "" {
+		config.NoProxy = s.NoProxy
+	}
+
+	if s.Password != "" && s.Username == "" {
+		return nil, fmt.Errorf("proxy_password requires proxy_username")
+	}
+	if s.Username != "" {
+		if config.HTTPProxy == "" && config.HTTPSProxy == "" {
+			return nil, fmt.Errorf("proxy_username requires proxy_url or the HTTPS_PROXY environment variable")
+		}
+
+		var err error
+		if config.HTTPProxy, err = withProxyCredentials(config.HTTPProxy, s.Username, s.Password); err != nil {
+			return nil, err
+		}
+		if config.HTTPSProxy, err = withProxyCredentials(config.HTTPSProxy, s.Username, s.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	proxyFunc := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// withProxyCredentials adds basic authentication credentials to a proxy URL. The transport
+// sends them to the proxy in the Proxy-Authorization header.
+func withProxyCredentials(proxy, username, password string) (string, error) {
+	if proxy == "" {
+		return "", nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return "", fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	proxyURL.User = url.UserPassword(username, password)
+	return proxyURL.String(), nil
+}
+
+// WithProxy sets the function that selects the proxy for each API request
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) ClientOption {
+	return func(c *Client) {
+		c.transport().Proxy = proxy
+	}
+}
+
+// proxySettingsFromConfig resolves the proxy settings from provider configuration with
+// environment variable and profile fallbacks
+func proxySettingsFromConfig(config DspcProviderModel, sources *configSources) ProxySettings {
+	return ProxySettings{
+		URL:      sources.stringSetting(config.ProxyURL, "DSPC_PROXY_URL"),
+		NoProxy:  sources.stringSetting(config.NoProxy, "DSPC_NO_PROXY"),
+		Username: sources.stringSetting(config.ProxyUsername, "DSPC_PROXY_USERNAME"),
+		Password: sources.stringSetting(config.ProxyPassword, "DSPC_PROXY_PASSWORD"),
+	}
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit limits the client to requestsPerSecond API requests per second on average,
+// allowing bursts of up to burst requests. A rate of zero or less removes the limit.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+}
+
+// WithMaxConcurrentRequests limits how many API requests the client has in flight at once.
+// A request counts as in flight until its response body is closed. A limit of zero or less
+// removes the cap.
+func WithMaxConcurrentRequests(limit int) ClientOption {
+	return func(c *Client) {
+		if limit <= 0 {
+			c.inflight = nil
+			return
+		}
+		c.inflight = make(chan struct{}, limit)
+	}
+}
+
+// acquire waits until the rate limit and the concurrency cap allow another request. The
+// returned function releases the concurrency slot and must be called exactly once.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.inflight != nil {
+		select {
+		case c.inflight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if c.inflight != nil {
+			<-c.inflight
+		}
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// releaseOnClose frees the concurrency slot of a request once its response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close closes the response body and releases the request's concurrency slot
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// holdUntilClosed keeps the concurrency slot of a request until resp's body is closed,
+// or releases it immediately when there is no response
+func holdUntilClosed(resp *http.Response, release func()) {
+	if resp == nil || resp.Body == nil {
+		release()
+		return
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+}
+
+// rateLimitFromConfig resolves the rate limit and concurrency cap from provider configuration
+// with environment variable and profile fallbacks. Zero means unlimited.
+func rateLimitFromConfig(config DspcProviderModel, sources *configSources) (int64, int64, error) {
+	requestsPerSecond, _, err := sources.int64Setting(config.MaxRequestsPerSecond, "DSPC_MAX_REQUESTS_PER_SECOND")
+	if err != nil {
+		return 0, 0, err
+	}
+	if requestsPerSecond < 0 {
+		return 0, 0, fmt.Errorf("max_requests_per_second must not be negative, got %d", requestsPerSecond)
+	}
+
+	concurrent, _, err := sources.int64Setting(config.MaxConcurrentRequests, "DSPC_MAX_CONCURRENT_REQUESTS")
+	if err != nil {
+		return 0, 0, err
+	}
+	if concurrent < 0 {
+		return 0, 0, fmt.Errorf("max_concurrent_requests must not be negative, got %d", concurrent)
+	}
+
+	return requestsPerSecond, concurrent, nil
+}
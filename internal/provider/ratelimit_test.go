@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestClient_MaxConcurrentRequests(t *testing.T) {
+	var current, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithMaxConcurrentRequests(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.do(context.Background(), http.MethodGet, vmPath, nil, nil); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak.Load())
+	}
+	if len(client.inflight) != 0 {
+		t.Errorf("Expected all concurrency slots to be released, got %d held", len(client.inflight))
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithRateLimit(20, 1))
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := client.do(context.Background(), http.MethodGet, vmPath, nil, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// The first request uses the burst, the other four wait 50ms each
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("Expected rate limited requests to take at least 180ms, took %s", elapsed)
+	}
+	if requests.Load() != 5 {
+		t.Errorf("Expected 5 requests, got %d", requests.Load())
+	}
+}
+
+func TestClient_RateLimitContextCanceled(t *testing.T) {
+	client := NewClient("http://localhost:1", "test-api-key", 30, WithMaxConcurrentRequests(1))
+	client.inflight <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := client.do(ctx, http.MethodGet, vmPath, nil, nil); err == nil {
+		t.Error("Expected error while waiting for a concurrency slot, got nil")
+	}
+}
+
+func TestNewClientFromConfig_RateLimit(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              DspcProviderModel
+		env                 map[string]string
+		expectedLimit       float64
+		expectedConcurrency int
+		expectError         bool
+	}{
+		{name: "unlimited by default"},
+		{
+			name: "attributes",
+			config: DspcProviderModel{
+				MaxRequestsPerSecond:  types.Int64Value(10),
+				MaxConcurrentRequests: types.Int64Value(4),
+			},
+			expectedLimit:       10,
+			expectedConcurrency: 4,
+		},
+		{
+			name: "environment variables",
+			env: map[string]string{
+				"DSPC_MAX_REQUESTS_PER_SECOND": "5",
+				"DSPC_MAX_CONCURRENT_REQUESTS": "2",
+			},
+			expectedLimit:       5,
+			expectedConcurrency: 2,
+		},
+		{
+			name:        "negative rate",
+			config:      DspcProviderModel{MaxRequestsPerSecond: types.Int64Value(-1)},
+			expectError: true,
+		},
+		{
+			name:        "invalid concurrency",
+			env:         map[string]string{"DSPC_MAX_CONCURRENT_REQUESTS": "lots"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, envVar := range []string{"DSPC_MAX_REQUESTS_PER_SECOND", "DSPC_MAX_CONCURRENT_REQUESTS"} {
+				t.Setenv(envVar, tt.env[envVar])
+			}
+
+			tt.config.Endpoint = types.StringValue("https://api.example.com")
+			tt.config.APIKey = types.StringValue("test-key")
+
+			client, err := NewClientFromConfig(tt.config)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var limit float64
+			if client.limiter != nil {
+				limit = float64(client.limiter.Limit())
+			}
+			if limit != tt.expectedLimit {
+				t.Errorf("Expected rate limit %v, got %v", tt.expectedLimit, limit)
+			}
+			if cap(client.inflight) != tt.expectedConcurrency {
+				t.Errorf("Expected concurrency cap %d, got %d", tt.expectedConcurrency, cap(client.inflight))
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries      = 3
+	defaultRetryMinBackoff = 1 * time.Second
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures how the client retries transient API failures.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the initial attempt. Zero disables retries.
+	MaxRetries int
+	// MinBackoff is the base delay before the first retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between retries, including delays requested via Retry-After.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultRetryMinBackoff,
+		MaxBackoff: defaultRetryMaxBackoff,
+	}
+}
+
+// isIdempotentMethod reports whether a request with the given method can be
+// repeated without changing the outcome on the server.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableStatus reports whether a response status indicates a transient failure.
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetryRequest decides whether a request should be attempted again. A request is
+// idempotent when its method is, or when an Idempotency-Key header makes it so. Non-idempotent
+// requests are only retried when the server cannot have acted on them: a 429 rejection or a
+// connection that was never established.
+func shouldRetryRequest(ctx context.Context, idempotent bool, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		if isPermanentTransportError(err) {
+			return false
+		}
+		if idempotent {
+			return true
+		}
+		return isDialError(err)
+	}
+
+	if idempotent {
+		return isRetryableStatus(resp.StatusCode)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests
+}
+
+// isDialError reports whether err happened while establishing the connection,
+// meaning no part of the request reached the server.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isPermanentTransportError reports whether a transport error will not go away on retry.
+func isPermanentTransportError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	return errors.Is(err, context.Canceled) || errors.As(err, &certErr)
+}
+
+// backoff returns how long to wait before retry number attempt (zero-based). A
+// Retry-After header on the response takes precedence over the computed delay.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(wait, p.MaxBackoff)
+		}
+	}
+
+	// Exponential backoff with full jitter between MinBackoff and the current ceiling.
+	ceiling := p.MinBackoff
+	for i := 0; i < attempt && ceiling < p.MaxBackoff; i++ {
+		ceiling *= 2
+	}
+	ceiling = min(ceiling, p.MaxBackoff)
+	if ceiling <= p.MinBackoff {
+		return ceiling
+	}
+
+	//nolint:gosec // jitter does not need a cryptographically secure source
+	return p.MinBackoff + rand.N(ceiling-p.MinBackoff+1)
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy keeps retry delays short so tests stay fast
+var testRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: 5 * time.Millisecond,
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		failStatus       int
+		failures         int32
+		expectedAttempts int32
+		expectError      bool
+	}{
+		{
+			name:             "GET retried on 503",
+			method:           http.MethodGet,
+			failStatus:       http.StatusServiceUnavailable,
+			failures:         2,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "GET gives up after max retries",
+			method:           http.MethodGet,
+			failStatus:       http.StatusBadGateway,
+			failures:         10,
+			expectedAttempts: 4,
+			expectError:      true,
+		},
+		{
+			name:             "GET not retried on 500",
+			method:           http.MethodGet,
+			failStatus:       http.StatusInternalServerError,
+			failures:         1,
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+		{
+			name:             "DELETE retried on 504",
+			method:           http.MethodDelete,
+			failStatus:       http.StatusGatewayTimeout,
+			failures:         1,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "POST retried on 429",
+			method:           http.MethodPost,
+			failStatus:       http.StatusTooManyRequests,
+			failures:         1,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "POST with idempotency key retried on 503",
+			method:           http.MethodPost,
+			failStatus:       http.StatusServiceUnavailable,
+			failures:         1,
+			expectedAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method {
+					t.Fatalf("Expected %s request, got %s", tt.method, r.Method)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				if attempts.Add(1) <= tt.failures {
+					w.WriteHeader(tt.failStatus)
+					_ = json.NewEncoder(w).Encode(map[string]string{"error": "transient"})
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				switch r.Method {
+				case http.MethodPost:
+					_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: "test-vm"})
+				case http.MethodDelete:
+					_ = json.NewEncoder(w).Encode(DeleteVMResponse{Deleted: "test-vm"})
+				default:
+					_ = json.NewEncoder(w).Encode([]*VM{{Name: "test-vm"}})
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(testRetryPolicy))
+
+			var err error
+			switch tt.method {
+			case http.MethodPost:
+				_, err = client.CreateVM(context.Background(), VM{Name: "test-vm"})
+			case http.MethodDelete:
+				err = client.DeleteVM(context.Background(), "test-vm")
+			default:
+				_, err = client.ListVMs(context.Background())
+			}
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if got := attempts.Load(); got != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestClient_RetryResendsRequestBody(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var vm VM
+		if err := json.NewDecoder(r.Body).Decode(&vm); err != nil {
+			t.Errorf("Attempt %d: failed to decode request body: %v", attempts.Load()+1, err)
+		}
+		if vm.Name != "test-vm" {
+			t.Errorf("Expected vmName test-vm, got %q", vm.Name)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(DeleteVMResponse{Deleted: vm.Name})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(testRetryPolicy))
+
+	if err := client.DeleteVM(context.Background(), "test-vm"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestClient_RetryDisabled(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(RetryPolicy{}))
+
+	if _, err := client.ListVMs(context.Background()); err == nil {
+		t.Error("Expected error, got nil")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestClient_RetryStopsOnContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.ListVMs(ctx)
+	if !isContextError(err) {
+		t.Errorf("Expected context error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected retry wait to be interrupted by context, took %s", elapsed)
+	}
+}
+
+func TestIsIdempotentMethod(t *testing.T) {
+	tests := map[string]bool{
+		http.MethodGet:    true,
+		http.MethodDelete: true,
+		http.MethodPut:    true,
+		http.MethodPost:   false,
+		http.MethodPatch:  false,
+	}
+
+	for method, expected := range tests {
+		if got := isIdempotentMethod(method); got != expected {
+			t.Errorf("Expected isIdempotentMethod(%s)=%t, got %t", method, expected, got)
+		}
+	}
+}
+
+func TestShouldRetryRequest(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	tests := []struct {
+		name       string
+		idempotent bool
+		status     int
+		err        error
+		expected   bool
+	}{
+		{name: "idempotent 503", idempotent: true, status: http.StatusServiceUnavailable, expected: true},
+		{name: "idempotent 429", idempotent: true, status: http.StatusTooManyRequests, expected: true},
+		{name: "idempotent 404", idempotent: true, status: http.StatusNotFound, expected: false},
+		{name: "idempotent 200", idempotent: true, status: http.StatusOK, expected: false},
+		{name: "idempotent connection reset", idempotent: true, err: readErr, expected: true},
+		{name: "non-idempotent 429", status: http.StatusTooManyRequests, expected: true},
+		{name: "non-idempotent 502", status: http.StatusBadGateway, expected: false},
+		{name: "non-idempotent dial error", err: dialErr, expected: true},
+		{name: "non-idempotent connection reset", err: readErr, expected: false},
+		{name: "idempotent canceled", idempotent: true, err: context.Canceled, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			}
+
+			if got := shouldRetryRequest(context.Background(), tt.idempotent, resp, tt.err); got != tt.expected {
+				t.Errorf("Expected shouldRetryRequest=%t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := policy.backoff(attempt, nil)
+		if wait < policy.MinBackoff || wait > policy.MaxBackoff {
+			t.Errorf("Attempt %d: backoff %s outside [%s, %s]", attempt, wait, policy.MinBackoff, policy.MaxBackoff)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	if wait := policy.backoff(0, resp); wait != policy.MaxBackoff {
+		t.Errorf("Expected Retry-After to be capped at %s, got %s", policy.MaxBackoff, wait)
+	}
+
+	resp.Header.Set("Retry-After", "0")
+	if wait := policy.backoff(3, resp); wait != 0 {
+		t.Errorf("Expected Retry-After of 0 to be honored, got %s", wait)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "empty", value: "", ok: false},
+		{name: "seconds", value: "5", expected: 5 * time.Second, ok: true},
+		{name: "negative seconds", value: "-1", ok: false},
+		{name: "HTTP date", value: "Mon, 01 Jan 2024 12:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{name: "HTTP date in the past", value: "Mon, 01 Jan 2024 11:00:00 GMT", expected: 0, ok: true},
+		{name: "garbage", value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok=%t, got %t", tt.ok, ok)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
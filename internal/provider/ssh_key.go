@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshKeyPath is the API collection of SSH public keys
+const sshKeyPath = "/sshkey"
+
+// SSHKey represents an SSH public key registered with the DSPC API
+type SSHKey struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"publicKey"`
+	// Fingerprint is the SHA256 fingerprint reported by the API, if any
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// SSHKeyUpdate contains the changes to apply to an SSH key
+type SSHKeyUpdate struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// CreateSSHKey registers a new SSH public key
+func (c *Client) CreateSSHKey(ctx context.Context, key SSHKey) (*SSHKey, error) {
+	var created SSHKey
+	if err := c.do(ctx, http.MethodPost, sshKeyPath, key, &created); err != nil {
+		return nil, err
+	}
+
+	// Servers may answer 204 No Content, in which case the request describes the key
+	if created.Name == "" {
+		created = key
+	}
+	return &created, nil
+}
+
+// GetSSHKey retrieves an SSH key by name. It returns an error wrapping ErrSSHKeyNotFound only
+// when the API confirmed that the key does not exist; any other error means the existence of
+// the key could not be determined.
+func (c *Client) GetSSHKey(ctx context.Context, name string) (*SSHKey, error) {
+	var key SSHKey
+	err := c.do(ctx, http.MethodGet, sshKeyPath+"/"+url.PathEscape(name), nil, &key)
+	switch {
+	case err == nil:
+	case apiErrorStatus(err) == http.StatusNotFound:
+		// A 404 also comes from a gateway or a server without the per-key endpoint, so the list
+		// decides whether the key exists
+		return c.findSSHKeyInList(ctx, name)
+	default:
+		return nil, err
+	}
+
+	if key.Name != name {
+		return nil, fmt.Errorf("unexpected response: expected SSH key '%s', got '%s'", name, key.Name)
+	}
+	return &key, nil
+}
+
+// findSSHKeyInList retrieves an SSH key by scanning the full SSH key list
+func (c *Client) findSSHKeyInList(ctx context.Context, name string) (*SSHKey, error) {
+	keys, err := c.ListSSHKeys(ctx)
+	if err != nil {
+		if apiErrorStatus(err) == http.StatusNotFound {
+			return nil, fmt.Errorf("neither the SSH key nor the SSH key list endpoint was found, check "+
+				"the API endpoint: %w", err)
+		}
+		return nil, err
+	}
+
+	for i := range keys {
+		if keys[i].Name == name {
+			return &keys[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: '%s' is not in the SSH key list. Please verify the SSH key name exists "+
+		"or check your API endpoint", ErrSSHKeyNotFound, name)
+}
+
+// ListSSHKeys retrieves all registered SSH keys
+func (c *Client) ListSSHKeys(ctx context.Context) ([]SSHKey, error) {
+	var keys []SSHKey
+	if err := c.do(ctx, http.MethodGet, sshKeyPath, nil, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// UpdateSSHKey replaces the public key of an existing SSH key. VMs that were created with the
+// key keep the previous public key.
+func (c *Client) UpdateSSHKey(ctx context.Context, name string, update SSHKeyUpdate) error {
+	return c.do(ctx, http.MethodPatch, sshKeyPath+"/"+url.PathEscape(name), update, nil)
+}
+
+// DeleteSSHKey deletes an SSH key by name
+func (c *Client) DeleteSSHKey(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, sshKeyPath+"/"+url.PathEscape(name), nil, nil)
+}
+
+// sshFingerprint returns the SHA256 fingerprint of a public key in authorized_keys format, as
+// printed by ssh-keygen -l
+func sshFingerprint(publicKey string) (string, error) {
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintSHA256(parsed), nil
+}
+
+// sameSSHKey reports whether two public keys are the same key, ignoring comments and
+// whitespace
+func sameSSHKey(a, b string) bool {
+	aFingerprint, err := sshFingerprint(a)
+	if err != nil {
+		return false
+	}
+	bFingerprint, err := sshFingerprint(b)
+	if err != nil {
+		return false
+	}
+	return aFingerprint == bFingerprint
+}
+
+// sshPublicKeyValidator checks that a value is a public key in authorized_keys format
+type sshPublicKeyValidator struct{}
+
+var _ validator.String = sshPublicKeyValidator{}
+
+// Description describes the validation in plain text formatting.
+func (v sshPublicKeyValidator) Description(_ context.Context) string {
+	return "value must be an SSH public key in authorized_keys format, such as the contents of ~/.ssh/id_ed25519.pub"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v sshPublicKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v sshPublicKeyValidator) ValidateString(
+	ctx context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := sshFingerprint(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid SSH Public Key",
+			fmt.Sprintf("%s: %s, got error: %s", req.Path, v.Description(ctx), err))
+	}
+}
@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &SSHKeyResource{}
+	_ resource.ResourceWithConfigure   = &SSHKeyResource{}
+	_ resource.ResourceWithImportState = &SSHKeyResource{}
+	_ resource.ResourceWithModifyPlan  = &SSHKeyResource{}
+)
+
+// SSHKeyResource defines the resource implementation.
+type SSHKeyResource struct {
+	client *Client
+}
+
+// SSHKeyResourceModel describes the resource data model.
+type SSHKeyResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	PublicKey   types.String `tfsdk:"public_key"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+}
+
+// NewSSHKeyResource creates a new SSHKeyResource.
+func NewSSHKeyResource() resource.Resource {
+	return &SSHKeyResource{}
+}
+
+// Metadata updates the provided metadata with the resource type name.
+func (r *SSHKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_key"
+}
+
+// Schema updates the resource schema with the attributes for the resource.
+func (r *SSHKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an SSH public key in the DSPC platform. Virtual machines list the keys to " +
+			"install in their ssh_key_names attribute.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The unique identifier for the SSH key.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the SSH key. Must be unique within the platform. Changing this " +
+					"replaces the SSH key.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"public_key": schema.StringAttribute{
+				Description: "The public key in authorized_keys format, such as the contents of " +
+					"~/.ssh/id_ed25519.pub. Can be changed without replacing the SSH key; virtual machines " +
+					"that were already created keep the previous key.",
+				Required: true,
+				Validators: []validator.String{
+					sshPublicKeyValidator{},
+				},
+			},
+			"fingerprint": schema.StringAttribute{
+				Description: "The SHA256 fingerprint of the public key, as shown by `ssh-keygen -l`.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure creates a new API client and stores it in the response data for the resource to use.
+func (r *SSHKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan computes the fingerprint of the planned public key, so it is known before apply,
+// and rejects the resource when the server does not support SSH keys.
+func (r *SSHKeyResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan SSHKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fingerprint := types.StringUnknown()
+	if !plan.PublicKey.IsUnknown() {
+		// The public key has passed validation, so it parses
+		value, _ := sshFingerprint(plan.PublicKey.ValueString())
+		fingerprint = types.StringValue(value)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("fingerprint"), fingerprint)...)
+
+	checkCapabilities(ctx, r.client, sshKeyCapabilityRequirements, map[string]attr.Value{
+		"name": plan.Name,
+	}, &resp.Diagnostics)
+}
+
+// Create registers a new SSH key in the DSPC platform.
+func (r *SSHKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := r.client.CreateSSHKey(ctx, SSHKey{
+		Name:      plan.Name.ValueString(),
+		PublicKey: plan.PublicKey.ValueString(),
+	})
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error creating SSH key", "create SSH key", err)
+		return
+	}
+
+	plan.ID = types.StringValue(key.Name)
+	plan.Name = types.StringValue(key.Name)
+	resp.Diagnostics.Append(plan.setFingerprint()...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read reads the data from the API and stores it in the state.
+func (r *SSHKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := r.client.GetSSHKey(ctx, state.Name.ValueString())
+	if err != nil {
+		if errors.Is(err, ErrSSHKeyNotFound) {
+			tflog.Warn(ctx, "SSH key no longer exists, removing from state", map[string]interface{}{
+				"name": state.Name.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "Error reading SSH key", "read SSH key", err)
+		return
+	}
+
+	state.ID = types.StringValue(key.Name)
+	state.Name = types.StringValue(key.Name)
+	// The configured formatting and comment are kept unless the key itself changed
+	if key.PublicKey != "" && !sameSSHKey(key.PublicKey, state.PublicKey.ValueString()) {
+		state.PublicKey = types.StringValue(key.PublicKey)
+	}
+	resp.Diagnostics.Append(state.setFingerprint()...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update replaces the public key of the SSH key.
+func (r *SSHKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = state.ID
+
+	if !plan.PublicKey.Equal(state.PublicKey) {
+		err := r.client.UpdateSSHKey(ctx, state.Name.ValueString(), SSHKeyUpdate{PublicKey: plan.PublicKey.ValueString()})
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Error updating SSH key", "update SSH key", err)
+			return
+		}
+	}
+	resp.Diagnostics.Append(plan.setFingerprint()...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the SSH key in the DSPC platform.
+func (r *SSHKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteSSHKey(ctx, state.Name.ValueString())
+	if IsNotFound(err) {
+		// A key that is already gone needs no further action, but a 404 may also come from a
+		// missing endpoint, so the API has to confirm that the key does not exist
+		if _, getErr := r.client.GetSSHKey(ctx, state.Name.ValueString()); errors.Is(getErr, ErrSSHKeyNotFound) {
+			return
+		}
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error deleting SSH key", "delete SSH key", err)
+	}
+}
+
+// ImportState imports the state of the SSH key in the DSPC platform.
+func (r *SSHKeyResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// setFingerprint computes the fingerprint attribute from the public key in the model.
+func (m *SSHKeyResourceModel) setFingerprint() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// An imported key whose public key the API does not report has no fingerprint
+	if m.PublicKey.IsNull() {
+		m.Fingerprint = types.StringNull()
+		return diags
+	}
+
+	fingerprint, err := sshFingerprint(m.PublicKey.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("public_key"), "Invalid SSH Public Key",
+			fmt.Sprintf("The public key of SSH key '%s' could not be parsed: %s", m.Name.ValueString(), err))
+		m.Fingerprint = types.StringNull()
+		return diags
+	}
+
+	m.Fingerprint = types.StringValue(fingerprint)
+	return diags
+}
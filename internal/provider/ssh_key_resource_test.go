@@ -0,0 +1,425 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newSSHKeyResourceState builds a resource state for the SSH key resource schema populated from model
+func newSSHKeyResourceState(t *testing.T, model SSHKeyResourceModel) tfsdk.State {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&SSHKeyResource{}).Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+	}
+	if diags := state.Set(context.Background(), &model); diags.HasError() {
+		t.Fatalf("Failed to build state: %v", diags)
+	}
+
+	return state
+}
+
+func TestSSHKeyResource_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockStatusCode int
+		expectError    bool
+	}{
+		{name: "successful creation", mockStatusCode: http.StatusCreated},
+		{name: "created without response body", mockStatusCode: http.StatusNoContent},
+		{name: "already exists", mockStatusCode: http.StatusConflict, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received SSHKey
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/sshkey" || r.Method != http.MethodPost {
+					t.Errorf("Expected POST /sshkey, got %s %s", r.Method, r.URL.Path)
+				}
+				_ = json.NewDecoder(r.Body).Decode(&received)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.mockStatusCode)
+				if tt.mockStatusCode == http.StatusCreated {
+					_ = json.NewEncoder(w).Encode(received)
+				}
+			}))
+			defer server.Close()
+
+			sshKeyResource := &SSHKeyResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+			plan := newSSHKeyResourceState(t, SSHKeyResourceModel{
+				ID:          types.StringUnknown(),
+				Name:        types.StringValue("alice"),
+				PublicKey:   types.StringValue(testSSHPublicKey),
+				Fingerprint: types.StringValue(testSSHFingerprint),
+			})
+			resp := &resource.CreateResponse{State: plan}
+
+			sshKeyResource.Create(context.Background(), resource.CreateRequest{
+				Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+			}, resp)
+
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Fatalf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+			if received.Name != "alice" || received.PublicKey != testSSHPublicKey {
+				t.Errorf("Expected request for key alice, got %+v", received)
+			}
+			if tt.expectError {
+				return
+			}
+
+			var state SSHKeyResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.ID.ValueString() != "alice" {
+				t.Errorf("Expected ID alice, got %s", state.ID)
+			}
+			if state.Fingerprint.ValueString() != testSSHFingerprint {
+				t.Errorf("Expected fingerprint %s, got %s", testSSHFingerprint, state.Fingerprint)
+			}
+		})
+	}
+}
+
+func TestSSHKeyResource_Read(t *testing.T) {
+	tests := []struct {
+		name              string
+		reportedKey       string
+		mockStatusCode    int
+		listStatusCode    int
+		listed            []SSHKey
+		expectError       bool
+		expectRemoved     bool
+		expectedPublicKey string
+	}{
+		{
+			name:              "same key with another comment",
+			reportedKey:       "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHRaDYGR9n6s5bxEevhf5U9SivwmM0FTT891q3aF3x9z",
+			mockStatusCode:    http.StatusOK,
+			expectedPublicKey: testSSHPublicKey,
+		},
+		{
+			name:              "key changed outside Terraform",
+			reportedKey:       otherTestSSHPublicKey,
+			mockStatusCode:    http.StatusOK,
+			expectedPublicKey: otherTestSSHPublicKey,
+		},
+		{
+			name:           "deleted outside Terraform",
+			mockStatusCode: http.StatusNotFound,
+			listStatusCode: http.StatusOK,
+			listed:         []SSHKey{{Name: "bob", PublicKey: otherTestSSHPublicKey}},
+			expectRemoved:  true,
+		},
+		{
+			name:              "key endpoint not found",
+			mockStatusCode:    http.StatusNotFound,
+			listStatusCode:    http.StatusOK,
+			listed:            []SSHKey{{Name: "alice", PublicKey: testSSHPublicKey}},
+			expectedPublicKey: testSSHPublicKey,
+		},
+		{
+			name:              "list endpoint not found keeps state",
+			mockStatusCode:    http.StatusNotFound,
+			listStatusCode:    http.StatusNotFound,
+			expectError:       true,
+			expectedPublicKey: testSSHPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/sshkey/alice":
+					// A bare 404 without a body, as returned for an unknown path
+					w.WriteHeader(tt.mockStatusCode)
+					if tt.mockStatusCode == http.StatusOK {
+						_ = json.NewEncoder(w).Encode(SSHKey{Name: "alice", PublicKey: tt.reportedKey})
+					}
+				case "/sshkey":
+					w.WriteHeader(tt.listStatusCode)
+					if tt.listStatusCode == http.StatusOK {
+						_ = json.NewEncoder(w).Encode(tt.listed)
+					}
+				default:
+					t.Errorf("Unexpected request path %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			sshKeyResource := &SSHKeyResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+			state := newSSHKeyResourceState(t, SSHKeyResourceModel{
+				ID:          types.StringValue("alice"),
+				Name:        types.StringValue("alice"),
+				PublicKey:   types.StringValue(testSSHPublicKey),
+				Fingerprint: types.StringValue(testSSHFingerprint),
+			})
+			resp := &resource.ReadResponse{State: state}
+
+			sshKeyResource.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Fatalf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+			if tt.expectRemoved {
+				if !resp.State.Raw.IsNull() {
+					t.Error("Expected SSH key to be removed from state")
+				}
+				return
+			}
+			if resp.State.Raw.IsNull() {
+				t.Fatal("Expected SSH key to be kept in state")
+			}
+
+			var result SSHKeyResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &result)...)
+			if result.PublicKey.ValueString() != tt.expectedPublicKey {
+				t.Errorf("Expected public key %q, got %q", tt.expectedPublicKey, result.PublicKey.ValueString())
+			}
+			expectedFingerprint, _ := sshFingerprint(tt.expectedPublicKey)
+			if result.Fingerprint.ValueString() != expectedFingerprint {
+				t.Errorf("Expected fingerprint %s, got %s", expectedFingerprint, result.Fingerprint)
+			}
+		})
+	}
+}
+
+func TestSSHKeyResource_Update(t *testing.T) {
+	var received SSHKeyUpdate
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/sshkey/alice" {
+			t.Errorf("Expected PATCH /sshkey/alice, got %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	sshKeyResource := &SSHKeyResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+	state := newSSHKeyResourceState(t, SSHKeyResourceModel{
+		ID:          types.StringValue("alice"),
+		Name:        types.StringValue("alice"),
+		PublicKey:   types.StringValue(testSSHPublicKey),
+		Fingerprint: types.StringValue(testSSHFingerprint),
+	})
+	plan := newSSHKeyResourceState(t, SSHKeyResourceModel{
+		ID:          types.StringValue("alice"),
+		Name:        types.StringValue("alice"),
+		PublicKey:   types.StringValue(otherTestSSHPublicKey),
+		Fingerprint: types.StringValue(otherTestSSHFingerprint),
+	})
+	resp := &resource.UpdateResponse{State: state}
+
+	sshKeyResource.Update(context.Background(), resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State: state,
+	}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+	}
+	if received.PublicKey != otherTestSSHPublicKey {
+		t.Errorf("Expected new public key in request, got %q", received.PublicKey)
+	}
+
+	var result SSHKeyResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &result)...)
+	if result.Fingerprint.ValueString() != otherTestSSHFingerprint {
+		t.Errorf("Expected fingerprint %s, got %s", otherTestSSHFingerprint, result.Fingerprint)
+	}
+}
+
+func TestSSHKeyResource_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockStatusCode int
+		listed         []SSHKey
+		expectError    bool
+	}{
+		{name: "successful deletion", mockStatusCode: http.StatusOK},
+		{name: "already deleted", mockStatusCode: http.StatusNotFound, listed: []SSHKey{}},
+		{
+			name:           "not found but still listed",
+			mockStatusCode: http.StatusNotFound,
+			listed:         []SSHKey{{Name: "alice", PublicKey: testSSHPublicKey}},
+			expectError:    true,
+		},
+		{name: "endpoint not found", mockStatusCode: http.StatusNotFound, expectError: true},
+		{name: "API error", mockStatusCode: http.StatusBadRequest, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodDelete && r.URL.Path == "/sshkey/alice":
+					w.WriteHeader(tt.mockStatusCode)
+				case r.Method == http.MethodGet && r.URL.Path == "/sshkey/alice":
+					w.WriteHeader(http.StatusNotFound)
+				case r.Method == http.MethodGet && r.URL.Path == "/sshkey" && tt.listed != nil:
+					_ = json.NewEncoder(w).Encode(tt.listed)
+				case r.Method == http.MethodGet && r.URL.Path == "/sshkey":
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			sshKeyResource := &SSHKeyResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+			state := newSSHKeyResourceState(t, SSHKeyResourceModel{
+				ID:          types.StringValue("alice"),
+				Name:        types.StringValue("alice"),
+				PublicKey:   types.StringValue(testSSHPublicKey),
+				Fingerprint: types.StringValue(testSSHFingerprint),
+			})
+			resp := &resource.DeleteResponse{State: state}
+
+			sshKeyResource.Delete(context.Background(), resource.DeleteRequest{State: state}, resp)
+
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Errorf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSSHKeyResource_ModifyPlan(t *testing.T) {
+	plan := newSSHKeyResourceState(t, SSHKeyResourceModel{
+		ID:          types.StringUnknown(),
+		Name:        types.StringValue("alice"),
+		PublicKey:   types.StringValue(testSSHPublicKey),
+		Fingerprint: types.StringUnknown(),
+	})
+	resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}
+
+	(&SSHKeyResource{}).ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+		Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+	}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+	}
+
+	var result SSHKeyResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(context.Background(), &result)...)
+	if result.Fingerprint.ValueString() != testSSHFingerprint {
+		t.Errorf("Expected planned fingerprint %s, got %s", testSSHFingerprint, result.Fingerprint)
+	}
+}
+
+func TestSSHKeyResource_ModifyPlan_Capabilities(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities string
+		expectError  bool
+	}{
+		{name: "supported version", capabilities: `{"apiVersion":"2.2"}`},
+		{name: "feature advertised", capabilities: `{"apiVersion":"2.0","features":["sshKeys"]}`},
+		{name: "older server", capabilities: `{"apiVersion":"2.1"}`, expectError: true},
+		{name: "capabilities not advertised", capabilities: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.capabilities == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.capabilities))
+			}))
+			defer server.Close()
+
+			sshKeyResource := &SSHKeyResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+			plan := newSSHKeyResourceState(t, SSHKeyResourceModel{
+				ID:          types.StringUnknown(),
+				Name:        types.StringValue("alice"),
+				PublicKey:   types.StringValue(testSSHPublicKey),
+				Fingerprint: types.StringUnknown(),
+			})
+			resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}
+
+			sshKeyResource.ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+			}, resp)
+
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Fatalf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+			if tt.expectError && !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "requires DSPC API >= 2.2") {
+				t.Errorf("Expected detail naming the required version, got %s", resp.Diagnostics.Errors()[0].Detail())
+			}
+		})
+	}
+}
+
+func TestVirtualMachineResource_CreateWithSSHKeyNames(t *testing.T) {
+	var received VM
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(received)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: received.Name})
+	}))
+	defer server.Close()
+
+	vmResource := &VMResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+	plan := newVMResourcePlan(t, vmResource, VMResourceModel{
+		ID:     types.StringUnknown(),
+		Name:   types.StringValue("test-vm"),
+		Image:  types.StringUnknown(),
+		Status: types.StringUnknown(),
+		SSHKeyNames: types.SetValueMust(types.StringType, []attr.Value{
+			types.StringValue("bob"), types.StringValue("alice"),
+		}),
+	})
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+
+	vmResource.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+	}
+
+	expected := []string{"alice", "bob"}
+	if !reflect.DeepEqual(received.SSHKeyNames, expected) {
+		t.Errorf("Expected SSH key names %v in request, got %v", expected, received.SSHKeyNames)
+	}
+
+	var state VMResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if len(state.SSHKeyNames.Elements()) != 2 {
+		t.Errorf("Expected 2 SSH key names in state, got %s", state.SSHKeyNames)
+	}
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// tlsVersions maps the accepted tls_min_version values to their crypto/tls constants
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// defaultTLSMinVersion is the minimum TLS version used when none is configured
+const defaultTLSMinVersion = "1.2"
+
+// TLSSettings describes how the client verifies the API server and authenticates itself
+// to it. Certificates and keys may be given either as PEM-encoded content or as paths to
+// PEM files.
+type TLSSettings struct {
+	// CACertFile is a path to a PEM bundle of additional trusted CA certificates
+	CACertFile string
+	// CACertPEM is a PEM bundle of additional trusted CA certificates
+	CACertPEM string
+	// ClientCert and ClientKey enable mutual TLS. Both or neither must be set.
+	ClientCert string
+	ClientKey  string
+	// MinVersion is the minimum TLS version, either "1.2" or "1.3"
+	MinVersion string
+	// InsecureSkipVerify disables server certificate verification
+	InsecureSkipVerify bool
+}
+
+// IsDefault reports whether the settings leave Go's default TLS behavior unchanged
+func (s TLSSettings) IsDefault() bool {
+	return s == TLSSettings{} || s == TLSSettings{MinVersion: defaultTLSMinVersion}
+}
+
+// Build creates the TLS configuration described by the settings
+func (s TLSSettings) Build() (*tls.Config, error) {
+	minVersion := s.MinVersion
+	if minVersion == "" {
+		minVersion = defaultTLSMinVersion
+	}
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TLS version %q, expected one of: 1.2, 1.3", minVersion)
+	}
+
+	config := &tls.Config{
+		MinVersion: version,
+		// Only set when explicitly requested for lab environments with self-signed certificates
+		InsecureSkipVerify: s.InsecureSkipVerify, //nolint:gosec // opt-in via insecure_skip_verify
+	}
+
+	if s.CACertFile != "" || s.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if s.CACertFile != "" {
+			pem, err := os.ReadFile(s.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no valid certificates found in CA certificate file %s", s.CACertFile)
+			}
+		}
+
+		if s.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(s.CACertPEM)) {
+			return nil, fmt.Errorf("no valid certificates found in ca_cert_pem")
+		}
+
+		config.RootCAs = pool
+	}
+
+	if (s.ClientCert == "") != (s.ClientKey == "") {
+		return nil, fmt.Errorf("client_cert and client_key must be set together")
+	}
+
+	if s.ClientCert != "" {
+		certPEM, err := pemOrFile(s.ClientCert, "client certificate")
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := pemOrFile(s.ClientKey, "client key")
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate and key: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// pemOrFile returns value itself when it contains PEM data, and otherwise reads it as a file path
+func pemOrFile(value, description string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s file: %w", description, err)
+	}
+	return data, nil
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the API
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.transport().TLSClientConfig = config
+	}
+}
+
+// insecureSkipVerify reports whether the client skips server certificate verification
+func (c *Client) insecureSkipVerify() bool {
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	return ok && transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify
+}
+
+// transport returns the client's HTTP transport, replacing the shared default transport
+// with a private copy on first use so it can be customized safely
+func (c *Client) transport() *http.Transport {
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+		return transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	c.httpClient.Transport = transport
+	return transport
+}
+
+// tlsSettingsFromConfig resolves the TLS settings from provider configuration with environment
+// variable and profile fallbacks
+func tlsSettingsFromConfig(config DspcProviderModel, sources *configSources) (TLSSettings, error) {
+	insecure, _, err := sources.boolSetting(config.InsecureSkipVerify, "DSPC_INSECURE_SKIP_VERIFY")
+	if err != nil {
+		return TLSSettings{}, err
+	}
+
+	return TLSSettings{
+		CACertFile:         sources.stringSetting(config.CACertFile, "DSPC_CA_CERT_FILE"),
+		CACertPEM:          sources.stringSetting(config.CACertPEM, "DSPC_CA_CERT_PEM"),
+		ClientCert:         sources.stringSetting(config.ClientCert, "DSPC_CLIENT_CERT"),
+		ClientKey:          sources.stringSetting(config.ClientKey, "DSPC_CLIENT_KEY"),
+		MinVersion:         sources.stringSetting(config.TLSMinVersion, "DSPC_TLS_MIN_VERSION"),
+		InsecureSkipVerify: insecure,
+	}, nil
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// newTLSTestServer starts a TLS server answering VM list requests
+func newTLSTestServer(t *testing.T, clientCAs *x509.CertPool) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*VM{{Name: "test-vm"}})
+	}))
+	if clientCAs != nil {
+		server.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// serverCAPEM returns the PEM-encoded certificate of a test TLS server
+func serverCAPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+// newClientCertificate creates a self-signed client certificate and returns it with its key as PEM
+func newClientCertificate(t *testing.T) (certPEM, keyPEM string, pool *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certPEM, keyPEM, pool
+}
+
+func TestTLSSettings_ServerVerification(t *testing.T) {
+	server := newTLSTestServer(t, nil)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(serverCAPEM(server)), 0o600); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		settings    TLSSettings
+		expectError bool
+	}{
+		{name: "untrusted server", settings: TLSSettings{}, expectError: true},
+		{name: "CA from PEM", settings: TLSSettings{CACertPEM: serverCAPEM(server)}},
+		{name: "CA from file", settings: TLSSettings{CACertFile: caFile}},
+		{name: "verification disabled", settings: TLSSettings{InsecureSkipVerify: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.settings.Build()
+			if err != nil {
+				t.Fatalf("Failed to build TLS config: %v", err)
+			}
+
+			client := NewClient(server.URL, "test-api-key", 30,
+				WithTLSConfig(config), WithRetryPolicy(RetryPolicy{}))
+
+			_, err = client.ListVMs(context.Background())
+			if tt.expectError && err == nil {
+				t.Error("Expected certificate verification error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSSettings_MutualTLS(t *testing.T) {
+	certPEM, keyPEM, pool := newClientCertificate(t)
+	server := newTLSTestServer(t, pool)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, []byte(certPEM), 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, []byte(keyPEM), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		cert, key   string
+		expectError bool
+	}{
+		{name: "no client certificate", expectError: true},
+		{name: "certificate as PEM", cert: certPEM, key: keyPEM},
+		{name: "certificate as files", cert: certFile, key: keyFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := TLSSettings{
+				CACertPEM:  serverCAPEM(server),
+				ClientCert: tt.cert,
+				ClientKey:  tt.key,
+			}.Build()
+			if err != nil {
+				t.Fatalf("Failed to build TLS config: %v", err)
+			}
+
+			client := NewClient(server.URL, "test-api-key", 30,
+				WithTLSConfig(config), WithRetryPolicy(RetryPolicy{}))
+
+			_, err = client.ListVMs(context.Background())
+			if tt.expectError && err == nil {
+				t.Error("Expected handshake error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSSettings_Build(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    TLSSettings
+		expectError bool
+		minVersion  uint16
+	}{
+		{name: "defaults", settings: TLSSettings{}, minVersion: tls.VersionTLS12},
+		{name: "TLS 1.3", settings: TLSSettings{MinVersion: "1.3"}, minVersion: tls.VersionTLS13},
+		{name: "unsupported version", settings: TLSSettings{MinVersion: "1.0"}, expectError: true},
+		{name: "invalid CA PEM", settings: TLSSettings{CACertPEM: "not a certificate"}, expectError: true},
+		{name: "missing CA file", settings: TLSSettings{CACertFile: "/nonexistent/ca.pem"}, expectError: true},
+		{name: "certificate without key", settings: TLSSettings{ClientCert: "cert.pem"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.settings.Build()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if config.MinVersion != tt.minVersion {
+				t.Errorf("Expected min version %x, got %x", tt.minVersion, config.MinVersion)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig_TLS(t *testing.T) {
+	server := newTLSTestServer(t, nil)
+
+	t.Setenv("DSPC_CA_CERT_PEM", serverCAPEM(server))
+
+	client, err := NewClientFromConfig(DspcProviderModel{
+		Endpoint: types.StringValue(server.URL),
+		APIKey:   types.StringValue("test-api-key"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.ListVMs(context.Background()); err != nil {
+		t.Errorf("Expected CA from environment to be trusted, got %v", err)
+	}
+
+	_, err = NewClientFromConfig(DspcProviderModel{
+		Endpoint:      types.StringValue(server.URL),
+		APIKey:        types.StringValue("test-api-key"),
+		TLSMinVersion: types.StringValue("1.1"),
+	})
+	if err == nil {
+		t.Error("Expected error for unsupported TLS version, got nil")
+	}
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxUserDataSize is the largest user data payload accepted, in bytes after base64 decoding
+const maxUserDataSize = 64 << 10
+
+// userDataValidator checks that user data fits in maxUserDataSize and, for user_data_base64,
+// that it is valid standard base64
+type userDataValidator struct {
+	base64 bool
+}
+
+var _ validator.String = userDataValidator{}
+
+// Description describes the validation in plain text formatting.
+func (v userDataValidator) Description(_ context.Context) string {
+	if v.base64 {
+		return fmt.Sprintf("value must be valid base64 that decodes to at most %d bytes", maxUserDataSize)
+	}
+	return fmt.Sprintf("value must be at most %d bytes", maxUserDataSize)
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v userDataValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v userDataValidator) ValidateString(
+	_ context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	payload := []byte(req.ConfigValue.ValueString())
+	if v.base64 {
+		decoded, err := base64.StdEncoding.DecodeString(req.ConfigValue.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid User Data",
+				fmt.Sprintf("%s must be valid base64: %s", req.Path, err))
+			return
+		}
+		payload = decoded
+	}
+
+	if len(payload) > maxUserDataSize {
+		resp.Diagnostics.AddAttributeError(req.Path, "User Data Too Large",
+			fmt.Sprintf("%s is %d bytes, more than the maximum of %d bytes.", req.Path, len(payload), maxUserDataSize))
+	}
+}
+
+// userDataHash returns the hex-encoded SHA-256 digest of a user data payload
+func userDataHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// userDataPayload returns the user data configured in the model, decoded if it was given as
+// user_data_base64. It returns nil when no user data is set.
+func (m *VMResourceModel) userDataPayload() []byte {
+	var payload []byte
+	switch {
+	case !m.UserData.IsNull() && !m.UserData.IsUnknown():
+		payload = []byte(m.UserData.ValueString())
+	case !m.UserDataBase64.IsNull() && !m.UserDataBase64.IsUnknown():
+		// The value has passed userDataValidator, so it decodes
+		payload, _ = base64.StdEncoding.DecodeString(m.UserDataBase64.ValueString())
+	}
+
+	if len(payload) == 0 {
+		return nil
+	}
+	return payload
+}
+
+// plannedUserDataSHA256 returns the user_data_sha256 value for the user data in the model. It
+// is unknown while the user data is unknown and null when there is none.
+func (m *VMResourceModel) plannedUserDataSHA256() types.String {
+	if m.UserData.IsUnknown() || m.UserDataBase64.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	payload := m.userDataPayload()
+	if payload == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(userDataHash(payload))
+}
+
+// encodedUserData returns the user data in the model as sent to the API: base64 encoded, or
+// empty when there is none
+func (m *VMResourceModel) encodedUserData() string {
+	payload := m.userDataPayload()
+	if payload == nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(payload)
+}
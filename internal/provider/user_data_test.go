@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testUserData = "#cloud-config\npackages:\n  - nginx\n"
+
+func TestUserDataValidator(t *testing.T) {
+	tests := []struct {
+		name            string
+		base64          bool
+		value           string
+		expectedSummary string
+	}{
+		{name: "raw", value: testUserData},
+		{name: "base64", base64: true, value: base64.StdEncoding.EncodeToString([]byte(testUserData))},
+		{name: "invalid base64", base64: true, value: testUserData, expectedSummary: "Invalid User Data"},
+		{name: "raw too large", value: strings.Repeat("a", maxUserDataSize+1), expectedSummary: "User Data Too Large"},
+		{
+			name:            "base64 too large",
+			base64:          true,
+			value:           base64.StdEncoding.EncodeToString(make([]byte, maxUserDataSize+1)),
+			expectedSummary: "User Data Too Large",
+		},
+		{
+			name:   "base64 at limit",
+			base64: true,
+			value:  base64.StdEncoding.EncodeToString(make([]byte, maxUserDataSize)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			userDataValidator{base64: tt.base64}.ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("user_data"),
+				ConfigValue: types.StringValue(tt.value),
+			}, resp)
+
+			if tt.expectedSummary == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Expected no error, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("Expected error, got none")
+			}
+			if summary := resp.Diagnostics.Errors()[0].Summary(); summary != tt.expectedSummary {
+				t.Errorf("Expected summary %q, got %q", tt.expectedSummary, summary)
+			}
+		})
+	}
+}
+
+func TestVMResourceModel_PlannedUserDataSHA256(t *testing.T) {
+	expected := types.StringValue(userDataHash([]byte(testUserData)))
+
+	raw := VMResourceModel{UserData: types.StringValue(testUserData)}
+	if result := raw.plannedUserDataSHA256(); !result.Equal(expected) {
+		t.Errorf("Expected %s for raw user data, got %s", expected, result)
+	}
+
+	encoded := VMResourceModel{UserDataBase64: types.StringValue(base64.StdEncoding.EncodeToString([]byte(testUserData)))}
+	if result := encoded.plannedUserDataSHA256(); !result.Equal(expected) {
+		t.Errorf("Expected %s for base64 user data, got %s", expected, result)
+	}
+
+	if result := (&VMResourceModel{}).plannedUserDataSHA256(); !result.IsNull() {
+		t.Errorf("Expected null without user data, got %s", result)
+	}
+
+	unknown := VMResourceModel{UserData: types.StringUnknown()}
+	if result := unknown.plannedUserDataSHA256(); !result.IsUnknown() {
+		t.Errorf("Expected unknown for unknown user data, got %s", result)
+	}
+}
+
+func TestVirtualMachineResource_ModifyPlan_UserData(t *testing.T) {
+	oldHash := types.StringValue(userDataHash([]byte("#cloud-config\n")))
+	newHash := types.StringValue(userDataHash([]byte(testUserData)))
+
+	tests := []struct {
+		name            string
+		stateHash       types.String
+		replaceOnChange bool
+		expectReplace   bool
+	}{
+		{name: "create", stateHash: types.StringNull(), replaceOnChange: true},
+		{name: "changed in place", stateHash: oldHash},
+		{name: "changed with replace on change", stateHash: oldHash, replaceOnChange: true, expectReplace: true},
+		{name: "unchanged with replace on change", stateHash: newHash, replaceOnChange: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmResource := &VMResource{}
+
+			model := VMResourceModel{
+				Name:                    types.StringValue("test-vm"),
+				UserData:                types.StringValue(testUserData),
+				UserDataReplaceOnChange: types.BoolValue(tt.replaceOnChange),
+			}
+			plan := newVMResourcePlan(t, vmResource, model)
+
+			// A null state stands for a VM that is about to be created
+			state := tfsdk.State{
+				Schema: plan.Schema,
+				Raw:    tftypes.NewValue(plan.Schema.Type().TerraformType(context.Background()), nil),
+			}
+			if !tt.stateHash.IsNull() {
+				state = newVMResourceState(t, vmResource, VMResourceModel{
+					Name:           types.StringValue("test-vm"),
+					UserDataSHA256: tt.stateHash,
+				})
+			}
+
+			resp := &resource.ModifyPlanResponse{Plan: plan}
+			vmResource.ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+				Plan:   plan,
+				State:  state,
+			}, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Expected no error, got %v", resp.Diagnostics)
+			}
+
+			var result VMResourceModel
+			resp.Diagnostics.Append(resp.Plan.Get(context.Background(), &result)...)
+			if !result.UserDataSHA256.Equal(newHash) {
+				t.Errorf("Expected user_data_sha256 %s, got %s", newHash, result.UserDataSHA256)
+			}
+			if replace := len(resp.RequiresReplace) > 0; replace != tt.expectReplace {
+				t.Errorf("Expected replace=%t, got %v", tt.expectReplace, resp.RequiresReplace)
+			}
+		})
+	}
+}
+
+func TestVirtualMachineResource_UserDataRoundTrip(t *testing.T) {
+	var received VM
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(received)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: received.Name})
+	}))
+	defer server.Close()
+
+	vmResource := &VMResource{client: NewClient(server.URL, "test-api-key", 30)}
+
+	plan := newVMResourcePlan(t, vmResource, VMResourceModel{
+		ID:             types.StringUnknown(),
+		Name:           types.StringValue("test-vm"),
+		Image:          types.StringUnknown(),
+		Status:         types.StringUnknown(),
+		UserData:       types.StringValue(testUserData),
+		UserDataSHA256: types.StringValue(userDataHash([]byte(testUserData))),
+	})
+	createResp := &resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+
+	vmResource.Create(context.Background(), resource.CreateRequest{Plan: plan}, createResp)
+
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", createResp.Diagnostics)
+	}
+	if expected := base64.StdEncoding.EncodeToString([]byte(testUserData)); received.UserData != expected {
+		t.Errorf("Expected base64 user data %q in request, got %q", expected, received.UserData)
+	}
+
+	// The user data is changed outside Terraform
+	received.UserData = base64.StdEncoding.EncodeToString([]byte("#cloud-config\n"))
+
+	readResp := &resource.ReadResponse{State: createResp.State}
+	vmResource.Read(context.Background(), resource.ReadRequest{State: createResp.State}, readResp)
+
+	var state VMResourceModel
+	readResp.Diagnostics.Append(readResp.State.Get(context.Background(), &state)...)
+	if expected := userDataHash([]byte("#cloud-config\n")); state.UserDataSHA256.ValueString() != expected {
+		t.Errorf("Expected user_data_sha256 %s after drift, got %s", expected, state.UserDataSHA256)
+	}
+	if state.UserData.ValueString() != testUserData {
+		t.Errorf("Expected configured user_data to be kept, got %s", state.UserData)
+	}
+}
@@ -3,9 +3,13 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -22,13 +26,24 @@ type VMDataSource struct {
 
 // VMDataSourceModel describes the data source data model.
 type VMDataSourceModel struct {
-	VirtualMachines []VMModel `tfsdk:"virtual_machines"`
+	NamePrefix      types.String `tfsdk:"name_prefix"`
+	NameRegex       types.String `tfsdk:"name_regex"`
+	Labels          types.Map    `tfsdk:"labels"`
+	Status          types.String `tfsdk:"status"`
+	VirtualMachines []VMModel    `tfsdk:"virtual_machines"`
 }
 
 // VMModel represents a single VM in the data source
 type VMModel struct {
-	ID   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	CPU         types.Int64  `tfsdk:"cpu"`
+	MemoryMB    types.Int64  `tfsdk:"memory_mb"`
+	DiskGB      types.Int64  `tfsdk:"disk_gb"`
+	Image       types.String `tfsdk:"image"`
+	Description types.String `tfsdk:"description"`
+	Status      types.String `tfsdk:"status"`
+	Labels      types.Map    `tfsdk:"labels"`
 }
 
 // NewVMDataSource creates a new VMDataSource.
@@ -44,8 +59,36 @@ func (d *VMDataSource) Metadata(_ context.Context, req datasource.MetadataReques
 // Schema updates the data source schema with the attributes for the data source.
 func (d *VMDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Retrieves a list of all virtual machines in the DSPC platform.",
+		Description: "Retrieves a list of virtual machines in the DSPC platform, optionally filtered by name, " +
+			"labels and status. Without filters all virtual machines are returned.",
 		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Description: "Only return virtual machines whose name starts with this prefix.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "Only return virtual machines whose name matches this regular expression " +
+					"(Go RE2 syntax). This filter is always applied by the provider.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"labels": schema.MapAttribute{
+				Description: "Only return virtual machines that have all of these labels with the given values.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Only return virtual machines with this provisioning status, for example `running`.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
 			"virtual_machines": schema.ListNestedAttribute{
 				Description: "List of virtual machines.",
 				Computed:    true,
@@ -59,6 +102,35 @@ func (d *VMDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, res
 							Description: "The name of the virtual machine.",
 							Computed:    true,
 						},
+						"cpu": schema.Int64Attribute{
+							Description: "The number of virtual CPUs, if reported by the API.",
+							Computed:    true,
+						},
+						"memory_mb": schema.Int64Attribute{
+							Description: "The amount of memory in MiB, if reported by the API.",
+							Computed:    true,
+						},
+						"disk_gb": schema.Int64Attribute{
+							Description: "The size of the boot disk in GiB, if reported by the API.",
+							Computed:    true,
+						},
+						"image": schema.StringAttribute{
+							Description: "The operating system image, if reported by the API.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "The description of the virtual machine, if any.",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "The provisioning status of the virtual machine, if reported by the API.",
+							Computed:    true,
+						},
+						"labels": schema.MapAttribute{
+							Description: "The labels of the virtual machine, if any.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
 					},
 				},
 			},
@@ -89,27 +161,89 @@ func (d *VMDataSource) Configure(
 }
 
 // Read reads the data from the API and stores it in the state.
-func (d *VMDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *VMDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state VMDataSourceModel
 
-	// Get all VMs from the API
-	vms, err := d.client.ListVMs(ctx)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := VMFilter{
+		NamePrefix: state.NamePrefix.ValueString(),
+		Status:     state.Status.ValueString(),
+	}
+
+	if !state.NameRegex.IsNull() {
+		re, err := regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid name_regex",
+				fmt.Sprintf("Could not compile regular expression: %s", err),
+			)
+			return
+		}
+		filter.NameRegex = re
+	}
+
+	if !state.Labels.IsNull() {
+		resp.Diagnostics.Append(state.Labels.ElementsAs(ctx, &filter.Labels, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Get the matching VMs from the API
+	vms, err := d.client.ListVMsFiltered(ctx, filter)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error listing VMs",
-			fmt.Sprintf("Could not list VMs: %s", err.Error()),
-		)
+		addAPIError(&resp.Diagnostics, "Error listing VMs", "list VMs", err)
 		return
 	}
 
 	// Convert API VMs to Terraform model
 	state.VirtualMachines = make([]VMModel, len(vms))
 	for i, vm := range vms {
-		state.VirtualMachines[i] = VMModel{
-			ID:   types.StringValue(vm.Name),
-			Name: types.StringValue(vm.Name),
-		}
+		state.VirtualMachines[i] = newVMModel(vm)
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
+
+// newVMModel converts an API VM into the data source model. Fields the API does not
+// report are null.
+func newVMModel(vm *VM) VMModel {
+	model := VMModel{
+		ID:          types.StringValue(vm.Name),
+		Name:        types.StringValue(vm.Name),
+		CPU:         types.Int64Null(),
+		MemoryMB:    types.Int64Null(),
+		DiskGB:      types.Int64Null(),
+		Image:       types.StringNull(),
+		Description: types.StringNull(),
+		Status:      types.StringNull(),
+	}
+
+	if vm.CPU != 0 {
+		model.CPU = types.Int64Value(vm.CPU)
+	}
+	if vm.MemoryMB != 0 {
+		model.MemoryMB = types.Int64Value(vm.MemoryMB)
+	}
+	if vm.DiskGB != 0 {
+		model.DiskGB = types.Int64Value(vm.DiskGB)
+	}
+	if vm.Image != "" {
+		model.Image = types.StringValue(vm.Image)
+	}
+	if vm.Description != "" {
+		model.Description = types.StringValue(vm.Description)
+	}
+	if vm.Status != "" {
+		model.Status = types.StringValue(vm.Status)
+	}
+	model.Labels = labelsValue(vm.Labels)
+
+	return model
+}
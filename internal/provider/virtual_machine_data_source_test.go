@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestVMDataSource_Read(t *testing.T) {
@@ -245,3 +248,76 @@ func TestVMDataSource_Read_EmptyResponse(t *testing.T) {
 		t.Errorf("Expected empty or nil VMs for null response, got %d VMs", len(vms))
 	}
 }
+
+func TestVMDataSource_Read_Filters(t *testing.T) {
+	tests := []struct {
+		name          string
+		nameRegex     string
+		expectError   bool
+		expectedNames []string
+	}{
+		{
+			name:          "regex filter",
+			nameRegex:     "^web-",
+			expectedNames: []string{"web-01", "web-02"},
+		},
+		{
+			name:        "invalid regex",
+			nameRegex:   "(",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode([]*VM{{Name: "web-01"}, {Name: "db-01"}, {Name: "web-02"}})
+			}))
+			defer server.Close()
+
+			dataSource := &VMDataSource{
+				client: NewClient(server.URL, "test-api-key", 30),
+			}
+
+			schemaResp := &datasource.SchemaResponse{}
+			dataSource.Schema(context.Background(), datasource.SchemaRequest{}, schemaResp)
+
+			state := tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+			}
+			config := VMDataSourceModel{
+				NamePrefix: types.StringNull(),
+				NameRegex:  types.StringValue(tt.nameRegex),
+				Labels:     types.MapNull(types.StringType),
+				Status:     types.StringNull(),
+			}
+			if diags := state.Set(context.Background(), &config); diags.HasError() {
+				t.Fatalf("Failed to build config: %v", diags)
+			}
+
+			req := datasource.ReadRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}
+			resp := &datasource.ReadResponse{State: state}
+			dataSource.Read(context.Background(), req, resp)
+
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Fatalf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+			if tt.expectError {
+				return
+			}
+
+			var result VMDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &result)...)
+			if len(result.VirtualMachines) != len(tt.expectedNames) {
+				t.Fatalf("Expected %d VMs, got %d", len(tt.expectedNames), len(result.VirtualMachines))
+			}
+			for i, name := range tt.expectedNames {
+				if got := result.VirtualMachines[i].Name.ValueString(); got != name {
+					t.Errorf("Expected VM %d to be %s, got %s", i, name, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &VMLookupDataSource{}
+	_ datasource.DataSourceWithConfigure = &VMLookupDataSource{}
+)
+
+// VMLookupDataSource defines the data source implementation for looking up a single VM by name.
+type VMLookupDataSource struct {
+	client *Client
+}
+
+// NewVMLookupDataSource creates a new VMLookupDataSource.
+func NewVMLookupDataSource() datasource.DataSource {
+	return &VMLookupDataSource{}
+}
+
+// Metadata updates the provided metadata with the data source type name.
+func (d *VMLookupDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_virtual_machine"
+}
+
+// Schema updates the data source schema with the attributes for the data source.
+func (d *VMLookupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Retrieves a single virtual machine in the DSPC platform by name.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The unique identifier for the virtual machine.",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the virtual machine to look up.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"cpu": schema.Int64Attribute{
+				Description: "The number of virtual CPUs, if reported by the API.",
+				Computed:    true,
+			},
+			"memory_mb": schema.Int64Attribute{
+				Description: "The amount of memory in MiB, if reported by the API.",
+				Computed:    true,
+			},
+			"disk_gb": schema.Int64Attribute{
+				Description: "The size of the boot disk in GiB, if reported by the API.",
+				Computed:    true,
+			},
+			"image": schema.StringAttribute{
+				Description: "The operating system image, if reported by the API.",
+				Computed:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "The description of the virtual machine, if any.",
+				Computed:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "The provisioning status of the virtual machine, if reported by the API.",
+				Computed:    true,
+			},
+			"labels": schema.MapAttribute{
+				Description: "The labels of the virtual machine, if any.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure stores the API client created by the provider for the data source to use.
+func (d *VMLookupDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+// Read looks up the VM by name and stores it in the state.
+func (d *VMLookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config VMModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := config.Name.ValueString()
+
+	vm, err := d.client.GetVM(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrVMNotFound) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"VM not found",
+				fmt.Sprintf("No virtual machine named '%s' exists in the DSPC platform.", name),
+			)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error reading VM", "read VM", err)
+		return
+	}
+
+	state := newVMModel(vm)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestVMLookupDataSource_Read(t *testing.T) {
+	tests := []struct {
+		name            string
+		mockResponse    interface{}
+		mockStatusCode  int
+		listNotFound    bool
+		expectError     bool
+		expectedSummary string
+	}{
+		{
+			name: "VM found",
+			mockResponse: VM{
+				Name:     "test-vm",
+				CPU:      2,
+				MemoryMB: 4096,
+				Image:    "ubuntu-22.04",
+				Status:   VMStatusRunning,
+			},
+			mockStatusCode: http.StatusOK,
+		},
+		{
+			name:            "VM not found",
+			mockResponse:    map[string]string{"error": "VM not found"},
+			mockStatusCode:  http.StatusNotFound,
+			expectError:     true,
+			expectedSummary: "VM not found",
+		},
+		{
+			name:            "VM list endpoint not found",
+			mockResponse:    map[string]string{"error": "Not found"},
+			mockStatusCode:  http.StatusNotFound,
+			listNotFound:    true,
+			expectError:     true,
+			expectedSummary: "Error reading VM",
+		},
+		{
+			name:            "API error",
+			mockResponse:    map[string]string{"error": "Internal server error"},
+			mockStatusCode:  http.StatusInternalServerError,
+			expectError:     true,
+			expectedSummary: "Error reading VM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				// Servers without the direct lookup endpoint report a missing VM through the list
+				if r.URL.Path == vmPath && tt.mockStatusCode == http.StatusNotFound && !tt.listNotFound {
+					_ = json.NewEncoder(w).Encode([]*VM{{Name: "other-vm"}})
+					return
+				}
+
+				w.WriteHeader(tt.mockStatusCode)
+				_ = json.NewEncoder(w).Encode(tt.mockResponse)
+			}))
+			defer server.Close()
+
+			dataSource := &VMLookupDataSource{
+				client: NewClient(server.URL, "test-api-key", 30, WithRetryPolicy(RetryPolicy{})),
+			}
+
+			schemaResp := &datasource.SchemaResponse{}
+			dataSource.Schema(context.Background(), datasource.SchemaRequest{}, schemaResp)
+
+			config := tfsdk.Config{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+			}
+			state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+			model := newVMModel(&VM{Name: "test-vm"})
+			model.ID = types.StringNull()
+			if diags := state.Set(context.Background(), &model); diags.HasError() {
+				t.Fatalf("Failed to build config: %v", diags)
+			}
+			config.Raw = state.Raw
+
+			resp := &datasource.ReadResponse{State: state}
+			dataSource.Read(context.Background(), datasource.ReadRequest{Config: config}, resp)
+
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Fatalf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+			if tt.expectError {
+				if !strings.HasPrefix(resp.Diagnostics[0].Summary(), tt.expectedSummary) {
+					t.Errorf("Expected summary %q, got %q", tt.expectedSummary, resp.Diagnostics[0].Summary())
+				}
+				return
+			}
+
+			var result VMModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &result)...)
+			if result.ID.ValueString() != "test-vm" || result.CPU.ValueInt64() != 2 ||
+				result.Image.ValueString() != "ubuntu-22.04" || result.Status.ValueString() != VMStatusRunning {
+				t.Errorf("Expected VM attributes in state, got %+v", result)
+			}
+			if !result.DiskGB.IsNull() {
+				t.Errorf("Expected unreported disk_gb to be null, got %s", result.DiskGB)
+			}
+		})
+	}
+}
+
+func TestVMLookupDataSource_Metadata(t *testing.T) {
+	dataSource := &VMLookupDataSource{}
+
+	resp := &datasource.MetadataResponse{}
+	dataSource.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "dspc"}, resp)
+
+	if resp.TypeName != "dspc_virtual_machine" {
+		t.Errorf("Expected type name 'dspc_virtual_machine', got '%s'", resp.TypeName)
+	}
+}
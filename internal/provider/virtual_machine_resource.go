@@ -2,14 +2,44 @@ package provider
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Default sizing applied to virtual machines that do not specify it.
+const (
+	defaultVMCPU      = 1
+	defaultVMMemoryMB = 1024
+	defaultVMDiskGB   = 20
+)
+
+// Default time limits for VM operations that wait for provisioning to complete.
+const (
+	defaultVMCreateTimeout = 20 * time.Minute
+	defaultVMUpdateTimeout = 20 * time.Minute
+	defaultVMDeleteTimeout = 20 * time.Minute
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -17,6 +47,7 @@ var (
 	_ resource.Resource                = &VMResource{}
 	_ resource.ResourceWithConfigure   = &VMResource{}
 	_ resource.ResourceWithImportState = &VMResource{}
+	_ resource.ResourceWithModifyPlan  = &VMResource{}
 )
 
 // VMResource defines the resource implementation.
@@ -26,8 +57,24 @@ type VMResource struct {
 
 // VMResourceModel describes the resource data model.
 type VMResourceModel struct {
-	ID   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	ID          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	CPU         types.Int64    `tfsdk:"cpu"`
+	MemoryMB    types.Int64    `tfsdk:"memory_mb"`
+	DiskGB      types.Int64    `tfsdk:"disk_gb"`
+	Image       types.String   `tfsdk:"image"`
+	Description types.String   `tfsdk:"description"`
+	Labels      types.Map      `tfsdk:"labels"`
+	LabelsAll   types.Map      `tfsdk:"labels_all"`
+	Status      types.String   `tfsdk:"status"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+
+	UserData                types.String `tfsdk:"user_data"`
+	UserDataBase64          types.String `tfsdk:"user_data_base64"`
+	UserDataReplaceOnChange types.Bool   `tfsdk:"user_data_replace_on_change"`
+	UserDataSHA256          types.String `tfsdk:"user_data_sha256"`
+
+	SSHKeyNames types.Set `tfsdk:"ssh_key_names"`
 }
 
 // NewVMResource creates a new VMResource.
@@ -41,7 +88,7 @@ func (r *VMResource) Metadata(_ context.Context, req resource.MetadataRequest, r
 }
 
 // Schema updates the resource schema with the attributes for the resource.
-func (r *VMResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *VMResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a virtual machine in the DSPC platform.",
 		Attributes: map[string]schema.Attribute{
@@ -50,12 +97,155 @@ func (r *VMResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 				Computed:    true,
 			},
 			"name": schema.StringAttribute{
-				Description: "The name of the virtual machine. Must be unique within the platform.",
-				Required:    true,
+				Description: "The name of the virtual machine. Must be unique within the platform. Changing " +
+					"this replaces the virtual machine.",
+				Required: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"cpu": schema.Int64Attribute{
+				Description: fmt.Sprintf("The number of virtual CPUs. Defaults to %d. Can be changed "+
+					"without replacing the virtual machine.", defaultVMCPU),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(defaultVMCPU),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 128),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					unrecordedSizePlanModifier{},
+				},
+			},
+			"memory_mb": schema.Int64Attribute{
+				Description: fmt.Sprintf("The amount of memory in MiB. Defaults to %d. Can be changed "+
+					"without replacing the virtual machine.", defaultVMMemoryMB),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(defaultVMMemoryMB),
+				Validators: []validator.Int64{
+					int64validator.Between(256, 1048576),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					unrecordedSizePlanModifier{},
+				},
+			},
+			"disk_gb": schema.Int64Attribute{
+				Description: fmt.Sprintf("The size of the boot disk in GiB. Defaults to %d. Changing this "+
+					"replaces the virtual machine.", defaultVMDiskGB),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(defaultVMDiskGB),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65536),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					unrecordedSizePlanModifier{},
+					int64planmodifier.RequiresReplaceIf(
+						requiresReplaceIfSizeRecorded,
+						"Changing the disk size of a virtual machine whose disk size is recorded replaces it.",
+						"Changing the disk size of a virtual machine whose disk size is recorded replaces it.",
+					),
+				},
+			},
+			"image": schema.StringAttribute{
+				Description: "The operating system image to deploy. When omitted, the platform default image " +
+					"is used and reported here. Changing this replaces the virtual machine.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					// Only a configured image replaces the VM. An image the API never reported is
+					// null in state and unknown in the plan, which is not a change.
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "A free-form description of the virtual machine. Can be changed without " +
+					"replacing the virtual machine.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(1024),
+				},
+			},
+			"labels": schema.MapAttribute{
+				Description: "Labels to attach to the virtual machine, for example to record the owning team or " +
+					"cost center. Merged with the provider's default_labels, which they override. Can be changed " +
+					"without replacing the virtual machine.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(labelKeyValidator()),
+				},
+			},
+			"labels_all": schema.MapAttribute{
+				Description: "All labels of the virtual machine: the provider's default_labels merged with labels.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"user_data": schema.StringAttribute{
+				Description: fmt.Sprintf("Cloud-init user data passed to the virtual machine, such as a "+
+					"`#cloud-config` document. At most %d bytes. Conflicts with user_data_base64. Changes are "+
+					"applied in place unless user_data_replace_on_change is set. Like all configured values, "+
+					"the user data is stored in the Terraform state, so do not put secrets in it.", maxUserDataSize),
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("user_data_base64")),
+					userDataValidator{},
+				},
+			},
+			"user_data_base64": schema.StringAttribute{
+				Description: fmt.Sprintf("Base64-encoded user data, for binary payloads such as gzip-compressed "+
+					"cloud-init configurations. At most %d bytes after decoding. Conflicts with user_data.",
+					maxUserDataSize),
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("user_data")),
+					userDataValidator{base64: true},
+				},
+			},
+			"user_data_replace_on_change": schema.BoolAttribute{
+				Description: "Replace the virtual machine when its user data changes, so the new user data " +
+					"is applied on first boot. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"user_data_sha256": schema.StringAttribute{
+				Description: "The SHA-256 digest of the user data sent to the API, used to detect changes made " +
+					"outside Terraform.",
+				Computed: true,
+			},
+			"ssh_key_names": schema.SetAttribute{
+				Description: "Names of dspc_ssh_key resources whose public keys are installed on the virtual " +
+					"machine when it is created. Changing this replaces the virtual machine.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "The provisioning status of the virtual machine as reported by the API, " +
+					"for example `running`.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -78,7 +268,84 @@ func (r *VMResource) Configure(_ context.Context, req resource.ConfigureRequest,
 	r.client = client
 }
 
-// Create creates a new virtual machine in the DSPC platform.
+// ModifyPlan computes labels_all from the provider's default labels and user_data_sha256 from
+// the user data, and rejects configured attributes that the DSPC API version in use does not
+// support, since older servers silently ignore them.
+func (r *VMResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	// Nothing is sent to the API when the VM is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, state VMResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The digest also changes when the user data was modified outside Terraform, since Read
+	// records the digest of the user data reported by the API
+	userDataSHA256 := config.plannedUserDataSHA256()
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("user_data_sha256"), userDataSHA256)...)
+	userDataChanged := !req.State.Raw.IsNull() && !userDataSHA256.Equal(state.UserDataSHA256)
+	if userDataChanged && config.UserDataReplaceOnChange.ValueBool() {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("user_data_sha256"))
+	}
+
+	// Without a configured client the default labels are not known yet, so labels_all stays unknown
+	if r.client == nil {
+		planVMStatus(ctx, req, resp, &state)
+		return
+	}
+
+	labelsAll := effectiveLabels(r.client.defaultLabels, config.Labels)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("labels_all"), labelsAll)...)
+	planVMStatus(ctx, req, resp, &state)
+
+	checkCapabilities(ctx, r.client, vmCapabilityRequirements, map[string]attr.Value{
+		"cpu":         config.CPU,
+		"memory_mb":   config.MemoryMB,
+		"disk_gb":     config.DiskGB,
+		"image":       config.Image,
+		"description": config.Description,
+		// Default labels are dropped by older servers just like configured ones
+		"labels":           labelsAll,
+		"user_data":        config.UserData,
+		"user_data_base64": config.UserDataBase64,
+		"ssh_key_names":    config.SSHKeyNames,
+	}, &resp.Diagnostics)
+}
+
+// planVMStatus marks the status unknown when an in-place update is planned, since Update
+// records the status the API reports once the VM is running again. It is called after the
+// computed attributes that feed into the update have been planned.
+func planVMStatus(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+	state *VMResourceModel,
+) {
+	if req.State.Raw.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan VMResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || vmUpdateFromModels(&plan, state).IsEmpty() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.StringUnknown())...)
+}
+
+// Create creates a new virtual machine in the DSPC platform and waits until it is running.
 func (r *VMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan VMResourceModel
 
@@ -88,20 +355,40 @@ func (r *VMResource) Create(ctx context.Context, req resource.CreateRequest, res
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultVMCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Create the VM via the API
-	vm, err := r.client.CreateVM(ctx, plan.Name.ValueString())
+	vm, err := r.client.CreateVM(ctx, plan.toVM())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating VM",
-			fmt.Sprintf("Could not create VM: %s", err.Error()),
-		)
+		addAPIError(&resp.Diagnostics, "Error creating VM", "create VM", err)
 		return
 	}
 
 	// Set the computed values
 	plan.ID = types.StringValue(vm.Name) // Using name as ID since API doesn't return separate ID
+	plan.update(vm)
+
+	// Wait for provisioning to finish
+	ready, err := r.client.WaitForVMRunning(ctx, vm.Name)
+	if ready != nil {
+		plan.update(ready)
+	}
+	plan.clearUnknown()
 
+	// The VM exists even if provisioning failed, so it is saved to state (and tainted by
+	// Terraform because of the error) rather than orphaned
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error waiting for VM to become ready", "provision VM", err)
+	}
 }
 
 // Read reads the data from the API and stores it in the state.
@@ -117,30 +404,85 @@ func (r *VMResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 	// Try to get the VM from the API
 	vm, err := r.client.GetVM(ctx, state.Name.ValueString())
 	if err != nil {
-		// If VM not found, remove from state
-		resp.State.RemoveResource(ctx)
+		// Only a confirmed absence removes the VM from state. Any other failure leaves the
+		// state untouched so Terraform does not plan to recreate a VM that may still exist.
+		if errors.Is(err, ErrVMNotFound) {
+			tflog.Warn(ctx, "VM no longer exists, removing from state", map[string]interface{}{
+				"name": state.Name.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "Error reading VM", "read VM", err)
 		return
 	}
 
 	// Update state with current values
 	state.ID = types.StringValue(vm.Name)
-	state.Name = types.StringValue(vm.Name)
+	state.update(vm)
+	if vm.Labels != nil {
+		state.Labels = resourceLabels(vm.Labels, r.client.defaultLabels, state.Labels)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-// Update updates the virtual machine in the DSPC platform.
-func (r *VMResource) Update(_ context.Context, _ resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Since the API only supports VM name and doesn't have update operations,
-	// we treat any changes as requiring recreation (ForceNew)
-	resp.Diagnostics.AddError(
-		"Update not supported",
-		"VM updates are not supported by the DSPC API. Changes require VM recreation. "+
-			"Consider using lifecycle { ignore_changes = [name] } if you need to prevent replacement.",
-	)
+// Update applies changes to the mutable attributes of the virtual machine in place and waits
+// until it is running again. Changes to immutable attributes are planned as replacements and
+// never reach this method.
+func (r *VMResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state VMResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultVMUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	plan.ID = state.ID
+	// A status planned as unknown is taken from the VM once the update is applied. Until then,
+	// and for servers that report no status, the previous status is kept.
+	if plan.Status.IsUnknown() {
+		plan.Status = state.Status
+	}
+
+	update := vmUpdateFromModels(&plan, &state)
+	if !update.IsEmpty() {
+		err := r.client.UpdateVM(ctx, state.Name.ValueString(), update)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Error updating VM", "update VM", err)
+			return
+		}
+
+		ready, err := r.client.WaitForVMRunning(ctx, state.Name.ValueString())
+		if ready != nil {
+			plan.update(ready)
+		}
+		if err != nil {
+			plan.clearUnknown()
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			addAPIError(&resp.Diagnostics, "Error waiting for VM to become ready", "update VM", err)
+			return
+		}
+	}
+
+	plan.clearUnknown()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-// Delete deletes the virtual machine in the DSPC platform.
+// Delete deletes the virtual machine in the DSPC platform and waits until it is gone.
 func (r *VMResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state VMResourceModel
 
@@ -150,15 +492,25 @@ func (r *VMResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 		return
 	}
 
-	// Delete the VM via the API
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultVMDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// Delete the VM via the API. A VM that is already gone needs no further action.
 	err := r.client.DeleteVM(ctx, state.Name.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting VM",
-			fmt.Sprintf("Could not delete VM: %s", err.Error()),
-		)
+	if err != nil && !IsNotFound(err) {
+		addAPIError(&resp.Diagnostics, "Error deleting VM", "delete VM", err)
 		return
 	}
+
+	if err := r.client.WaitForVMDeleted(ctx, state.Name.ValueString()); err != nil {
+		addAPIError(&resp.Diagnostics, "Error waiting for VM deletion", "confirm VM deletion", err)
+	}
 }
 
 // ImportState imports the state of the virtual machine in the DSPC platform.
@@ -169,3 +521,169 @@ func (r *VMResource) ImportState(
 ) {
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
+
+// toVM converts the resource model into a VM specification for the API.
+func (m *VMResourceModel) toVM() VM {
+	return VM{
+		Name:        m.Name.ValueString(),
+		CPU:         m.CPU.ValueInt64(),
+		MemoryMB:    m.MemoryMB.ValueInt64(),
+		DiskGB:      m.DiskGB.ValueInt64(),
+		Image:       m.Image.ValueString(),
+		Description: m.Description.ValueString(),
+		Labels:      labelsMap(m.LabelsAll),
+		UserData:    m.encodedUserData(),
+		SSHKeyNames: sshKeyNames(m.SSHKeyNames),
+	}
+}
+
+// update copies the values reported by the API into the resource model. Fields the API
+// does not report are left unchanged, so servers implementing only the minimal API keep
+// the configured values.
+func (m *VMResourceModel) update(vm *VM) {
+	m.Name = types.StringValue(vm.Name)
+
+	if vm.CPU != 0 {
+		m.CPU = types.Int64Value(vm.CPU)
+	}
+	if vm.MemoryMB != 0 {
+		m.MemoryMB = types.Int64Value(vm.MemoryMB)
+	}
+	if vm.DiskGB != 0 {
+		m.DiskGB = types.Int64Value(vm.DiskGB)
+	}
+	if vm.Image != "" {
+		m.Image = types.StringValue(vm.Image)
+	}
+	if vm.Description != "" {
+		m.Description = types.StringValue(vm.Description)
+	}
+	if vm.Status != "" {
+		m.Status = types.StringValue(vm.Status)
+	}
+	if vm.Labels != nil {
+		m.LabelsAll = labelsValue(vm.Labels)
+	}
+	if vm.UserData != "" {
+		if payload, err := base64.StdEncoding.DecodeString(vm.UserData); err == nil {
+			m.UserDataSHA256 = types.StringValue(userDataHash(payload))
+		}
+	}
+	if len(vm.SSHKeyNames) > 0 {
+		names := make([]attr.Value, 0, len(vm.SSHKeyNames))
+		for _, name := range vm.SSHKeyNames {
+			names = append(names, types.StringValue(name))
+		}
+		m.SSHKeyNames = types.SetValueMust(types.StringType, names)
+	}
+}
+
+// sshKeyNames returns the SSH key names in a set attribute in sorted order, or nil when there
+// are none
+func sshKeyNames(value types.Set) []string {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	var names []string
+	for _, element := range value.Elements() {
+		if name, ok := element.(types.String); ok && !name.IsNull() && !name.IsUnknown() {
+			names = append(names, name.ValueString())
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// clearUnknown replaces computed values the API did not report with null, since Terraform
+// does not accept unknown values after apply.
+func (m *VMResourceModel) clearUnknown() {
+	if m.Image.IsUnknown() {
+		m.Image = types.StringNull()
+	}
+	if m.Status.IsUnknown() {
+		m.Status = types.StringNull()
+	}
+	if m.LabelsAll.IsUnknown() {
+		m.LabelsAll = types.MapNull(types.StringType)
+	}
+	if m.UserDataSHA256.IsUnknown() {
+		m.UserDataSHA256 = types.StringNull()
+	}
+}
+
+// vmUpdateFromModels returns the changes needed to bring the VM from its current state to the
+// planned values. Only attributes that differ are included.
+func vmUpdateFromModels(plan, state *VMResourceModel) VMUpdate {
+	var update VMUpdate
+
+	if !plan.CPU.Equal(state.CPU) {
+		update.CPU = plan.CPU.ValueInt64Pointer()
+	}
+	if !plan.MemoryMB.Equal(state.MemoryMB) {
+		update.MemoryMB = plan.MemoryMB.ValueInt64Pointer()
+	}
+	if !plan.Description.Equal(state.Description) {
+		// An empty description clears the one stored by the API
+		description := plan.Description.ValueString()
+		update.Description = &description
+	}
+	if !plan.LabelsAll.Equal(state.LabelsAll) && !plan.LabelsAll.IsUnknown() {
+		labels := labelsMap(plan.LabelsAll)
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		update.Labels = &labels
+	}
+	if !plan.UserDataSHA256.Equal(state.UserDataSHA256) && !plan.UserDataSHA256.IsUnknown() {
+		// Empty user data removes the user data stored by the API
+		userData := plan.encodedUserData()
+		update.UserData = &userData
+	}
+
+	return update
+}
+
+// unrecordedSizePlanModifier keeps a sizing attribute null when the state has no value for it and
+// the configuration does not set it. This is the case for VMs created before the provider managed
+// sizing, on servers that do not report it. Without it the default would plan a change to the
+// default size, updating or replacing every such VM.
+type unrecordedSizePlanModifier struct{}
+
+var _ planmodifier.Int64 = unrecordedSizePlanModifier{}
+
+// Description describes the plan modification in plain text formatting.
+func (m unrecordedSizePlanModifier) Description(_ context.Context) string {
+	return "keeps the value unset when it was never recorded and is not configured"
+}
+
+// MarkdownDescription describes the plan modification in Markdown formatting.
+func (m unrecordedSizePlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+// PlanModifyInt64 implements the plan modification.
+func (m unrecordedSizePlanModifier) PlanModifyInt64(
+	_ context.Context,
+	req planmodifier.Int64Request,
+	resp *planmodifier.Int64Response,
+) {
+	// New VMs get the default size
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	if req.StateValue.IsNull() && req.ConfigValue.IsNull() {
+		resp.PlanValue = types.Int64Null()
+	}
+}
+
+// requiresReplaceIfSizeRecorded requires replacement only when the prior size is known. Setting
+// the size of a VM whose size was never recorded records the configured value instead.
+func requiresReplaceIfSizeRecorded(
+	_ context.Context,
+	req planmodifier.Int64Request,
+	resp *int64planmodifier.RequiresReplaceIfFuncResponse,
+) {
+	resp.RequiresReplace = !req.StateValue.IsNull()
+}
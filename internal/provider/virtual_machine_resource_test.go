@@ -3,11 +3,21 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestVirtualMachineResource_Create(t *testing.T) {
@@ -52,7 +62,7 @@ func TestVirtualMachineResource_Create(t *testing.T) {
 			}
 
 			// Test the client directly instead of the resource methods
-			vm, err := vmResource.client.CreateVM(context.Background(), tt.vmName)
+			vm, err := vmResource.client.CreateVM(context.Background(), VM{Name: tt.vmName})
 
 			if tt.expectError {
 				if err == nil {
@@ -170,8 +180,10 @@ func TestVirtualMachineResource_ImportState(t *testing.T) {
 				if r.Method != http.MethodGet {
 					t.Fatalf("Expected GET request, got %s", r.Method)
 				}
+				// Simulate a server without the per-VM lookup endpoint
 				if r.URL.Path != vmPath {
-					t.Fatalf("Expected /virtualmachine path, got %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					return
 				}
 
 				w.Header().Set("Content-Type", "application/json")
@@ -205,15 +217,773 @@ func TestVirtualMachineResource_ImportState(t *testing.T) {
 }
 
 func TestVirtualMachineResource_Update(t *testing.T) {
-	vmResource := &VMResource{}
+	current := VMResourceModel{
+		ID:          types.StringValue("test-vm"),
+		Name:        types.StringValue("test-vm"),
+		CPU:         types.Int64Value(2),
+		MemoryMB:    types.Int64Value(2048),
+		DiskGB:      types.Int64Value(20),
+		Image:       types.StringValue("ubuntu-22.04"),
+		Description: types.StringValue("web server"),
+		Labels:      types.MapNull(types.StringType),
+		LabelsAll:   labelsValue(map[string]string{"team": "platform"}),
+		SSHKeyNames: types.SetNull(types.StringType),
+		Status:      types.StringValue(VMStatusRunning),
+		Timeouts:    nullVMTimeouts(),
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(m *VMResourceModel)
+		mockStatusCode int
+		expectRequest  bool
+		expectedBody   map[string]interface{}
+		expectError    bool
+	}{
+		{
+			name:           "resize cpu only",
+			modify:         func(m *VMResourceModel) { m.CPU = types.Int64Value(4) },
+			mockStatusCode: http.StatusOK,
+			expectRequest:  true,
+			expectedBody:   map[string]interface{}{"cpu": float64(4)},
+		},
+		{
+			name: "resize memory and change description",
+			modify: func(m *VMResourceModel) {
+				m.MemoryMB = types.Int64Value(8192)
+				m.Description = types.StringValue("database server")
+			},
+			mockStatusCode: http.StatusOK,
+			expectRequest:  true,
+			expectedBody:   map[string]interface{}{"memoryMb": float64(8192), "description": "database server"},
+		},
+		{
+			name:           "remove description",
+			modify:         func(m *VMResourceModel) { m.Description = types.StringNull() },
+			mockStatusCode: http.StatusOK,
+			expectRequest:  true,
+			expectedBody:   map[string]interface{}{"description": ""},
+		},
+		{
+			name: "change labels",
+			modify: func(m *VMResourceModel) {
+				m.Labels = labelsValue(map[string]string{"cost-center": "1234"})
+				m.LabelsAll = labelsValue(map[string]string{"team": "platform", "cost-center": "1234"})
+			},
+			mockStatusCode: http.StatusOK,
+			expectRequest:  true,
+			expectedBody: map[string]interface{}{
+				"labels": map[string]interface{}{"team": "platform", "cost-center": "1234"},
+			},
+		},
+		{
+			name:           "remove all labels",
+			modify:         func(m *VMResourceModel) { m.LabelsAll = types.MapNull(types.StringType) },
+			mockStatusCode: http.StatusOK,
+			expectRequest:  true,
+			expectedBody:   map[string]interface{}{"labels": map[string]interface{}{}},
+		},
+		{
+			name: "change user data in place",
+			modify: func(m *VMResourceModel) {
+				m.UserData = types.StringValue("#cloud-config\n")
+				m.UserDataSHA256 = types.StringValue(userDataHash([]byte("#cloud-config\n")))
+			},
+			mockStatusCode: http.StatusOK,
+			expectRequest:  true,
+			expectedBody:   map[string]interface{}{"userData": "I2Nsb3VkLWNvbmZpZwo="},
+		},
+		{
+			name:           "no mutable changes",
+			modify:         func(*VMResourceModel) {},
+			mockStatusCode: http.StatusOK,
+			expectRequest:  false,
+		},
+		{
+			name:           "API error",
+			modify:         func(m *VMResourceModel) { m.CPU = types.Int64Value(64) },
+			mockStatusCode: http.StatusBadRequest,
+			expectRequest:  true,
+			expectedBody:   map[string]interface{}{"cpu": float64(64)},
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(VM{Name: "test-vm", Status: VMStatusRunning})
+					return
+				}
+
+				requested = true
+				if r.Method != http.MethodPatch {
+					t.Errorf("Expected PATCH request, got %s", r.Method)
+				}
+				if r.URL.Path != vmPath+"/test-vm" {
+					t.Errorf("Expected %s/test-vm path, got %s", vmPath, r.URL.Path)
+				}
+
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				if len(body) != len(tt.expectedBody) {
+					t.Errorf("Expected request body %v, got %v", tt.expectedBody, body)
+				}
+				for key, value := range tt.expectedBody {
+					if !reflect.DeepEqual(body[key], value) {
+						t.Errorf("Expected %s=%v in request body, got %v", key, value, body[key])
+					}
+				}
+
+				w.WriteHeader(tt.mockStatusCode)
+			}))
+			defer server.Close()
+
+			vmResource := &VMResource{
+				client: NewClient(server.URL, "test-api-key", 30, WithPollInterval(time.Millisecond)),
+			}
+
+			planned := current
+			tt.modify(&planned)
+
+			state := newVMResourceState(t, vmResource, current)
+			req := resource.UpdateRequest{
+				Plan:  newVMResourcePlan(t, vmResource, planned),
+				State: state,
+			}
+			resp := &resource.UpdateResponse{State: state}
+
+			vmResource.Update(context.Background(), req, resp)
+
+			if requested != tt.expectRequest {
+				t.Errorf("Expected API request=%t, got %t", tt.expectRequest, requested)
+			}
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Fatalf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+			if tt.expectError {
+				return
+			}
+
+			var result VMResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &result)...)
+			if !reflect.DeepEqual(result, planned) {
+				t.Errorf("Expected state %+v, got %+v", planned, result)
+			}
+		})
+	}
+}
+
+// newVMResourceState builds a resource state for the VM resource schema populated from model
+func newVMResourceState(t *testing.T, vmResource *VMResource, model VMResourceModel) tfsdk.State {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	vmResource.Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+
+	if len(model.Timeouts.Object.AttributeTypes(context.Background())) == 0 {
+		model.Timeouts = nullVMTimeouts()
+	}
+	if model.Labels.ElementType(context.Background()) == nil {
+		model.Labels = types.MapNull(types.StringType)
+	}
+	if model.LabelsAll.ElementType(context.Background()) == nil {
+		model.LabelsAll = types.MapNull(types.StringType)
+	}
+	if model.SSHKeyNames.ElementType(context.Background()) == nil {
+		model.SSHKeyNames = types.SetNull(types.StringType)
+	}
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+	}
+	if diags := state.Set(context.Background(), &model); diags.HasError() {
+		t.Fatalf("Failed to build state: %v", diags)
+	}
+
+	return state
+}
+
+// nullVMTimeouts returns an unset timeouts block for the VM resource schema
+func nullVMTimeouts() timeouts.Value {
+	return timeouts.Value{
+		Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"update": types.StringType,
+			"delete": types.StringType,
+		}),
+	}
+}
+
+func TestVirtualMachineResource_Read(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockResponse   interface{}
+		mockStatusCode int
+		expectError    bool
+		expectRemoved  bool
+	}{
+		{
+			name:           "VM exists",
+			mockResponse:   []*VM{{Name: "test-vm"}},
+			mockStatusCode: http.StatusOK,
+		},
+		{
+			name:           "VM confirmed absent",
+			mockResponse:   []*VM{{Name: "other-vm"}},
+			mockStatusCode: http.StatusOK,
+			expectRemoved:  true,
+		},
+		{
+			name:           "unauthorized keeps state",
+			mockResponse:   map[string]string{"error": "invalid token"},
+			mockStatusCode: http.StatusUnauthorized,
+			expectError:    true,
+		},
+		{
+			name:           "server error keeps state",
+			mockResponse:   map[string]string{"error": "Internal server error"},
+			mockStatusCode: http.StatusInternalServerError,
+			expectError:    true,
+		},
+		{
+			// A 404 from the list endpoint means the VMs could not be listed, for example
+			// because of a wrong base path, not that the VM is gone
+			name:           "list endpoint not found keeps state",
+			mockResponse:   map[string]string{"error": "Not found"},
+			mockStatusCode: http.StatusNotFound,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.mockStatusCode)
+				_ = json.NewEncoder(w).Encode(tt.mockResponse)
+			}))
+			defer server.Close()
+
+			vmResource := &VMResource{
+				client: NewClient(server.URL, "test-api-key", 30),
+			}
+
+			state := newVMResourceState(t, vmResource, VMResourceModel{
+				ID:   types.StringValue("test-vm"),
+				Name: types.StringValue("test-vm"),
+			})
+			req := resource.ReadRequest{State: state}
+			resp := &resource.ReadResponse{State: state}
+
+			vmResource.Read(context.Background(), req, resp)
+
+			if tt.expectError != resp.Diagnostics.HasError() {
+				t.Errorf("Expected error=%t, got diagnostics: %v", tt.expectError, resp.Diagnostics)
+			}
+			if removed := resp.State.Raw.IsNull(); removed != tt.expectRemoved {
+				t.Errorf("Expected removed=%t, got %t", tt.expectRemoved, removed)
+			}
+		})
+	}
+}
+
+func TestVirtualMachineResource_Read_NetworkErrorKeepsState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	endpoint := server.URL
+	server.Close()
 
-	req := resource.UpdateRequest{}
-	resp := &resource.UpdateResponse{}
+	vmResource := &VMResource{
+		client: NewClient(endpoint, "test-api-key", 30, WithRetryPolicy(RetryPolicy{})),
+	}
 
-	vmResource.Update(context.Background(), req, resp)
+	state := newVMResourceState(t, vmResource, VMResourceModel{
+		ID:   types.StringValue("test-vm"),
+		Name: types.StringValue("test-vm"),
+	})
+	resp := &resource.ReadResponse{State: state}
+
+	vmResource.Read(context.Background(), resource.ReadRequest{State: state}, resp)
 
-	// Update should always return an error
 	if !resp.Diagnostics.HasError() {
-		t.Error("Expected error from Update, got none")
+		t.Error("Expected error diagnostic for unreachable API")
+	}
+	if resp.State.Raw.IsNull() {
+		t.Error("Expected state to be kept when the API is unreachable")
+	}
+}
+
+// newVMResourcePlan builds a resource plan for the VM resource schema populated from model
+func newVMResourcePlan(t *testing.T, vmResource *VMResource, model VMResourceModel) tfsdk.Plan {
+	t.Helper()
+
+	state := newVMResourceState(t, vmResource, model)
+	return tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}
+}
+
+func TestVirtualMachineResource_CreateWithSpecification(t *testing.T) {
+	var received VM
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(received)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(CreateVMResponse{Created: received.Name})
+	}))
+	defer server.Close()
+
+	vmResource := &VMResource{
+		client: NewClient(server.URL, "test-api-key", 30, WithPollInterval(time.Millisecond)),
+	}
+
+	plan := newVMResourcePlan(t, vmResource, VMResourceModel{
+		ID:       types.StringUnknown(),
+		Name:     types.StringValue("test-vm"),
+		CPU:      types.Int64Value(4),
+		MemoryMB: types.Int64Value(8192),
+		DiskGB:   types.Int64Value(100),
+		Image:    types.StringUnknown(),
+		Status:   types.StringUnknown(),
+	})
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+
+	vmResource.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+	}
+
+	expected := VM{Name: "test-vm", CPU: 4, MemoryMB: 8192, DiskGB: 100}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("Expected request %+v, got %+v", expected, received)
+	}
+
+	var state VMResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.ID.ValueString() != "test-vm" {
+		t.Errorf("Expected ID test-vm, got %s", state.ID)
+	}
+	if state.CPU.ValueInt64() != 4 || state.MemoryMB.ValueInt64() != 8192 || state.DiskGB.ValueInt64() != 100 {
+		t.Errorf("Expected sizing to round-trip, got cpu=%s memory_mb=%s disk_gb=%s",
+			state.CPU, state.MemoryMB, state.DiskGB)
+	}
+	if !state.Image.IsNull() {
+		t.Errorf("Expected unreported image to be null, got %s", state.Image)
+	}
+	if !state.Status.IsNull() {
+		t.Errorf("Expected unreported status to be null, got %s", state.Status)
+	}
+}
+
+func TestVirtualMachineResource_ReadUpdatesSpecification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(VM{Name: "test-vm", CPU: 8, Image: "ubuntu-24.04"})
+	}))
+	defer server.Close()
+
+	vmResource := &VMResource{
+		client: NewClient(server.URL, "test-api-key", 30),
+	}
+
+	state := newVMResourceState(t, vmResource, VMResourceModel{
+		ID:       types.StringValue("test-vm"),
+		Name:     types.StringValue("test-vm"),
+		CPU:      types.Int64Value(2),
+		MemoryMB: types.Int64Value(2048),
+		DiskGB:   types.Int64Value(20),
+		Image:    types.StringValue("ubuntu-22.04"),
+	})
+	resp := &resource.ReadResponse{State: state}
+
+	vmResource.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+	}
+
+	var model VMResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &model)...)
+	if model.CPU.ValueInt64() != 8 || model.Image.ValueString() != "ubuntu-24.04" {
+		t.Errorf("Expected drift to be detected, got cpu=%s image=%s", model.CPU, model.Image)
+	}
+	if model.MemoryMB.ValueInt64() != 2048 {
+		t.Errorf("Expected unreported memory_mb to keep its state value, got %s", model.MemoryMB)
+	}
+}
+
+func TestVirtualMachineResource_Schema(t *testing.T) {
+	vmResource := &VMResource{}
+
+	resp := &resource.SchemaResponse{}
+	vmResource.Schema(context.Background(), resource.SchemaRequest{}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Resource schema has errors: %v", resp.Diagnostics)
+	}
+
+	for _, name := range []string{"id", "name", "cpu", "memory_mb", "disk_gb", "image", "description", "status"} {
+		if _, ok := resp.Schema.Attributes[name]; !ok {
+			t.Errorf("Resource schema missing '%s' attribute", name)
+		}
+	}
+	if _, ok := resp.Schema.Blocks["timeouts"]; !ok {
+		t.Error("Resource schema missing 'timeouts' block")
+	}
+}
+
+// planVMResourceChange plans a change of the VM resource through the provider server, so defaults
+// and attribute plan modifiers are applied as Terraform applies them. A nil prior stands for a VM
+// that is about to be created. It returns the planned attribute values and the attributes whose
+// change replaces the VM.
+func planVMResourceChange(
+	t *testing.T,
+	prior, config map[string]tftypes.Value,
+) (map[string]tftypes.Value, map[string]bool) {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&VMResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	object := func(values map[string]tftypes.Value) tftypes.Value {
+		if values == nil {
+			return tftypes.NewValue(objectType, nil)
+		}
+		attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for name, typ := range objectType.AttributeTypes {
+			attributes[name] = tftypes.NewValue(typ, nil)
+			if value, ok := values[name]; ok {
+				attributes[name] = value
+			}
+		}
+		return tftypes.NewValue(objectType, attributes)
+	}
+
+	// Terraform proposes the prior value for computed attributes that are not configured
+	proposed := make(map[string]tftypes.Value, len(config))
+	for name, value := range config {
+		proposed[name] = value
+	}
+	for name, attribute := range schemaResp.Schema.Attributes {
+		if _, ok := config[name]; !ok && attribute.IsComputed() && prior != nil {
+			if value, ok := prior[name]; ok {
+				proposed[name] = value
+			}
+		}
+	}
+
+	dynamicValue := func(value tftypes.Value) *tfprotov6.DynamicValue {
+		result, err := tfprotov6.NewDynamicValue(objectType, value)
+		if err != nil {
+			t.Fatalf("Failed to build dynamic value: %v", err)
+		}
+		return &result
+	}
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("Failed to create provider server: %v", err)
+	}
+
+	resp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "dspc_virtual_machine",
+		PriorState:       dynamicValue(object(prior)),
+		ProposedNewState: dynamicValue(object(proposed)),
+		Config:           dynamicValue(object(config)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to plan: %v", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("Expected no error, got %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	planned, err := resp.PlannedState.Unmarshal(objectType)
+	if err != nil {
+		t.Fatalf("Failed to read planned state: %v", err)
+	}
+	var values map[string]tftypes.Value
+	if err := planned.As(&values); err != nil {
+		t.Fatalf("Failed to read planned state: %v", err)
+	}
+
+	replaced := make(map[string]bool, len(resp.RequiresReplace))
+	for _, attributePath := range resp.RequiresReplace {
+		if steps := attributePath.Steps(); len(steps) > 0 {
+			if name, ok := steps[0].(tftypes.AttributeName); ok {
+				replaced[string(name)] = true
+			}
+		}
+	}
+
+	return values, replaced
+}
+
+func TestVirtualMachineResource_PlanSizing(t *testing.T) {
+	// State written before the provider managed sizing, on a server that does not report it
+	legacy := map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "test-vm"),
+		"name": tftypes.NewValue(tftypes.String, "test-vm"),
+	}
+	recorded := map[string]tftypes.Value{
+		"id":        tftypes.NewValue(tftypes.String, "test-vm"),
+		"name":      tftypes.NewValue(tftypes.String, "test-vm"),
+		"cpu":       tftypes.NewValue(tftypes.Number, 1),
+		"memory_mb": tftypes.NewValue(tftypes.Number, 1024),
+		"disk_gb":   tftypes.NewValue(tftypes.Number, 20),
+	}
+	name := tftypes.NewValue(tftypes.String, "test-vm")
+
+	tests := []struct {
+		name          string
+		prior         map[string]tftypes.Value
+		config        map[string]tftypes.Value
+		expected      map[string]interface{}
+		expectReplace bool
+	}{
+		{
+			name:     "create uses defaults",
+			config:   map[string]tftypes.Value{"name": name},
+			expected: map[string]interface{}{"cpu": int64(1), "memory_mb": int64(1024), "disk_gb": int64(20)},
+		},
+		{
+			name:     "unrecorded sizing stays unset",
+			prior:    legacy,
+			config:   map[string]tftypes.Value{"name": name},
+			expected: map[string]interface{}{"cpu": nil, "memory_mb": nil, "disk_gb": nil},
+		},
+		{
+			name:  "configured disk size on unrecorded sizing is recorded",
+			prior: legacy,
+			config: map[string]tftypes.Value{
+				"name":    name,
+				"disk_gb": tftypes.NewValue(tftypes.Number, 50),
+			},
+			expected: map[string]interface{}{"cpu": nil, "disk_gb": int64(50)},
+		},
+		{
+			name:     "recorded sizing keeps defaults",
+			prior:    recorded,
+			config:   map[string]tftypes.Value{"name": name},
+			expected: map[string]interface{}{"cpu": int64(1), "memory_mb": int64(1024), "disk_gb": int64(20)},
+		},
+		{
+			name:  "changed disk size replaces",
+			prior: recorded,
+			config: map[string]tftypes.Value{
+				"name":    name,
+				"disk_gb": tftypes.NewValue(tftypes.Number, 50),
+			},
+			expected:      map[string]interface{}{"disk_gb": int64(50)},
+			expectReplace: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned, replaced := planVMResourceChange(t, tt.prior, tt.config)
+
+			if replaced["disk_gb"] != tt.expectReplace {
+				t.Errorf("Expected disk_gb to require replacement=%t, got %t", tt.expectReplace, replaced["disk_gb"])
+			}
+			for attribute, expected := range tt.expected {
+				value := planned[attribute]
+				if expected == nil {
+					if !value.IsNull() {
+						t.Errorf("Expected %s to be null, got %s", attribute, value)
+					}
+					continue
+				}
+
+				var number big.Float
+				if err := value.As(&number); err != nil {
+					t.Fatalf("Expected %s to be a number, got %s", attribute, value)
+				}
+				if result, _ := number.Int64(); result != expected.(int64) {
+					t.Errorf("Expected %s to be %d, got %d", attribute, expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestVirtualMachineResource_PlanImage(t *testing.T) {
+	name := tftypes.NewValue(tftypes.String, "test-vm")
+	state := func(image interface{}) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"id":        tftypes.NewValue(tftypes.String, "test-vm"),
+			"name":      name,
+			"cpu":       tftypes.NewValue(tftypes.Number, 1),
+			"memory_mb": tftypes.NewValue(tftypes.Number, 1024),
+			"disk_gb":   tftypes.NewValue(tftypes.Number, 20),
+			"image":     tftypes.NewValue(tftypes.String, image),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		prior         map[string]tftypes.Value
+		config        map[string]tftypes.Value
+		expectReplace bool
+	}{
+		{
+			name:  "in-place change without reported image",
+			prior: state(nil),
+			config: map[string]tftypes.Value{
+				"name": name,
+				"cpu":  tftypes.NewValue(tftypes.Number, 2),
+			},
+		},
+		{
+			name:  "in-place change with reported image",
+			prior: state("ubuntu-22.04"),
+			config: map[string]tftypes.Value{
+				"name": name,
+				"cpu":  tftypes.NewValue(tftypes.Number, 2),
+			},
+		},
+		{
+			name:  "changed image",
+			prior: state("ubuntu-22.04"),
+			config: map[string]tftypes.Value{
+				"name":  name,
+				"image": tftypes.NewValue(tftypes.String, "debian-12"),
+			},
+			expectReplace: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, replaced := planVMResourceChange(t, tt.prior, tt.config)
+
+			if len(replaced) > 0 != tt.expectReplace {
+				t.Errorf("Expected replace=%t, got attributes %v", tt.expectReplace, replaced)
+			}
+		})
+	}
+}
+
+func TestVirtualMachineResource_PlanStatus(t *testing.T) {
+	name := tftypes.NewValue(tftypes.String, "test-vm")
+	prior := map[string]tftypes.Value{
+		"id":        tftypes.NewValue(tftypes.String, "test-vm"),
+		"name":      name,
+		"cpu":       tftypes.NewValue(tftypes.Number, 1),
+		"memory_mb": tftypes.NewValue(tftypes.Number, 1024),
+		"disk_gb":   tftypes.NewValue(tftypes.Number, 20),
+		"status":    tftypes.NewValue(tftypes.String, "stopped"),
+	}
+
+	tests := []struct {
+		name          string
+		config        map[string]tftypes.Value
+		expectUnknown bool
+	}{
+		{
+			name:   "no changes",
+			config: map[string]tftypes.Value{"name": name},
+		},
+		{
+			name: "in-place update",
+			config: map[string]tftypes.Value{
+				"name": name,
+				"cpu":  tftypes.NewValue(tftypes.Number, 2),
+			},
+			expectUnknown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned, _ := planVMResourceChange(t, prior, tt.config)
+
+			status := planned["status"]
+			if status.IsKnown() == tt.expectUnknown {
+				t.Fatalf("Expected status unknown=%t, got %s", tt.expectUnknown, status)
+			}
+			if !tt.expectUnknown && !status.Equal(prior["status"]) {
+				t.Errorf("Expected status %s, got %s", prior["status"], status)
+			}
+		})
+	}
+}
+
+func TestVirtualMachineResource_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		reportedStatus string
+		expected       types.String
+	}{
+		{name: "stopped VM reported running", reportedStatus: VMStatusRunning, expected: types.StringValue(VMStatusRunning)},
+		{name: "status not reported", expected: types.StringValue("stopped")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(VM{Name: "test-vm", CPU: 2, Status: tt.reportedStatus})
+				}
+			}))
+			defer server.Close()
+
+			vmResource := &VMResource{
+				client: NewClient(server.URL, "test-api-key", 30, WithPollInterval(time.Millisecond)),
+			}
+
+			current := VMResourceModel{
+				ID:       types.StringValue("test-vm"),
+				Name:     types.StringValue("test-vm"),
+				CPU:      types.Int64Value(1),
+				MemoryMB: types.Int64Value(1024),
+				DiskGB:   types.Int64Value(20),
+				Image:    types.StringNull(),
+				Status:   types.StringValue("stopped"),
+			}
+			planned := current
+			planned.CPU = types.Int64Value(2)
+			planned.Status = types.StringUnknown()
+
+			state := newVMResourceState(t, vmResource, current)
+			resp := &resource.UpdateResponse{State: state}
+
+			vmResource.Update(context.Background(), resource.UpdateRequest{
+				Plan:  newVMResourcePlan(t, vmResource, planned),
+				State: state,
+			}, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Expected no error, got: %v", resp.Diagnostics)
+			}
+
+			var result VMResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &result)...)
+			if !result.Status.Equal(tt.expected) {
+				t.Errorf("Expected status %s, got %s", tt.expected, result.Status)
+			}
+			if result.CPU.ValueInt64() != 2 {
+				t.Errorf("Expected cpu 2, got %s", result.CPU)
+			}
+		})
 	}
 }
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultPollInterval is how often the client checks on a VM while waiting for it
+const defaultPollInterval = 5 * time.Second
+
+// VM statuses reported by the DSPC API. Any other status is treated as in progress.
+const (
+	VMStatusRunning = "running"
+	VMStatusFailed  = "failed"
+	VMStatusError   = "error"
+)
+
+// ErrVMFailed is returned when a VM enters a failed status while the client waits for it
+var ErrVMFailed = errors.New("VM provisioning failed")
+
+// WithPollInterval sets how often the client checks on a VM while waiting for it
+func WithPollInterval(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.pollInterval = interval
+	}
+}
+
+// WaitForVMRunning polls the VM until it reports the running status, enters a failed status or
+// ctx expires. VMs without a status, as returned by servers implementing only the minimal API,
+// are considered running as soon as they exist.
+func (c *Client) WaitForVMRunning(ctx context.Context, name string) (*VM, error) {
+	var lastStatus string
+	timedOut := func(err error) error {
+		return fmt.Errorf("timed out waiting for VM '%s' to become %s (last status: %q): %w",
+			name, VMStatusRunning, lastStatus, err)
+	}
+
+	for {
+		// Every poll needs fresh data, so the shared VM list cache is bypassed rather than
+		// invalidated, which would defeat it for every other resource in the apply
+		vm, err := c.lookupVM(ctx, name, c.fetchVMs)
+		switch {
+		case err == nil:
+			lastStatus = vm.Status
+			switch strings.ToLower(vm.Status) {
+			case "", VMStatusRunning:
+				return vm, nil
+			case VMStatusFailed, VMStatusError:
+				return vm, fmt.Errorf("%w: VM '%s' entered status '%s'", ErrVMFailed, name, vm.Status)
+			}
+		case errors.Is(err, ErrVMNotFound):
+			// A VM that was just created may not be visible yet
+		case ctx.Err() != nil:
+			// The deadline expired during the request
+			return nil, timedOut(ctx.Err())
+		default:
+			return nil, err
+		}
+
+		if err := sleepContext(ctx, c.pollInterval); err != nil {
+			return nil, timedOut(err)
+		}
+	}
+}
+
+// WaitForVMDeleted polls the VM list until the VM no longer appears in it or ctx expires
+func (c *Client) WaitForVMDeleted(ctx context.Context, name string) error {
+	timedOut := func(err error) error {
+		return fmt.Errorf("timed out waiting for VM '%s' to be deleted: %w", name, err)
+	}
+
+	for {
+		// The cache is bypassed since a cached list would still contain the VM
+		vms, err := c.fetchVMs(ctx)
+		switch {
+		case err == nil:
+			if !containsVM(vms, name) {
+				c.vmList.invalidate()
+				return nil
+			}
+		case ctx.Err() != nil:
+			// The deadline expired during the request
+			return timedOut(ctx.Err())
+		default:
+			return err
+		}
+
+		if err := sleepContext(ctx, c.pollInterval); err != nil {
+			return timedOut(err)
+		}
+	}
+}
+
+// containsVM reports whether vms contains a VM with the given name
+func containsVM(vms []*VM, name string) bool {
+	for _, vm := range vms {
+		if vm != nil && vm.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WaitForVMRunning(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []string
+		expectError   error
+		expectedPolls int32
+	}{
+		{
+			name:          "becomes running",
+			statuses:      []string{"provisioning", "provisioning", VMStatusRunning},
+			expectedPolls: 3,
+		},
+		{
+			name:          "no status reported",
+			statuses:      []string{""},
+			expectedPolls: 1,
+		},
+		{
+			name:          "provisioning failed",
+			statuses:      []string{"provisioning", VMStatusFailed},
+			expectError:   ErrVMFailed,
+			expectedPolls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != vmPath+"/test-vm" {
+					t.Errorf("Expected %s/test-vm path, got %s", vmPath, r.URL.Path)
+				}
+
+				n := int(polls.Add(1))
+				status := tt.statuses[min(n, len(tt.statuses))-1]
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(VM{Name: "test-vm", Status: status})
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-api-key", 30, WithPollInterval(time.Millisecond))
+
+			vm, err := client.WaitForVMRunning(context.Background(), "test-vm")
+			if tt.expectError != nil {
+				if !errors.Is(err, tt.expectError) {
+					t.Errorf("Expected %v, got %v", tt.expectError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if vm == nil || vm.Status != tt.statuses[len(tt.statuses)-1] {
+				t.Errorf("Expected final VM with status %q, got %+v", tt.statuses[len(tt.statuses)-1], vm)
+			}
+			if got := polls.Load(); got != tt.expectedPolls {
+				t.Errorf("Expected %d polls, got %d", tt.expectedPolls, got)
+			}
+		})
+	}
+}
+
+func TestClient_WaitForVMRunning_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(VM{Name: "test-vm", Status: "provisioning"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithPollInterval(5*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForVMRunning(ctx, "test-vm")
+	if err == nil || !strings.Contains(err.Error(), `last status: "provisioning"`) {
+		t.Errorf("Expected timeout error with last status, got %v", err)
+	}
+}
+
+func TestClient_WaitForVMDeleted(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != vmPath {
+			t.Errorf("Expected GET %s, got %s %s", vmPath, r.Method, r.URL.Path)
+		}
+
+		vms := []*VM{{Name: "other-vm"}}
+		if polls.Add(1) < 3 {
+			vms = append(vms, &VM{Name: "test-vm", Status: "deleting"})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(vms)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithPollInterval(time.Millisecond))
+
+	if err := client.WaitForVMDeleted(context.Background(), "test-vm"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := polls.Load(); got != 3 {
+		t.Errorf("Expected 3 polls, got %d", got)
+	}
+}
+
+func TestClient_WaitForVMRunning_KeepsListCache(t *testing.T) {
+	var listCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != vmPath {
+			// A server without the per-VM endpoint
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		status := "provisioning"
+		if listCalls.Add(1) > 2 {
+			status = VMStatusRunning
+		}
+		_ = json.NewEncoder(w).Encode([]*VM{{Name: "test-vm", Status: status}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-api-key", 30, WithPollInterval(time.Millisecond))
+
+	if _, err := client.ListVMs(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.WaitForVMRunning(context.Background(), "test-vm"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	calls := listCalls.Load()
+
+	// Polling must not invalidate the list shared with other resources
+	if _, err := client.ListVMs(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := listCalls.Load(); got != calls {
+		t.Errorf("Expected the cached VM list to be reused, got %d list requests instead of %d", got, calls)
+	}
+}